@@ -0,0 +1,67 @@
+package anneal
+
+import "math"
+
+// An Acceptor makes the entire decision whether to adopt each proposed State,
+// in place of the Metropolis criterion and Schedule.AcceptProb.
+// It might consult a learned policy or a remote service, or implement another dynamics
+// such as Demon, ThresholdAccepting, or GreatDeluge.
+// Anneal calls Accept for every proposal, including those of lower energy, on the goroutine that called Anneal.
+type Acceptor interface {
+	// Accept reports whether to adopt d.Proposed. An error ends the search.
+	Accept(d Decision) (bool, error)
+}
+
+// An AcceptorFunc is a function that implements Acceptor.
+type AcceptorFunc func(d Decision) (bool, error)
+
+func (f AcceptorFunc) Accept(d Decision) (bool, error) { return f(d) }
+
+// A Decision describes a proposal awaiting acceptance.
+// The Acceptor may derive features for its decision from the States.
+type Decision struct {
+	Run         int     // index of the run, counting restarts
+	Iter        int     // iteration of the run
+	Temperature float64 // temperature at iteration Iter
+	Delta       float64 // energy of Proposed minus energy of Current, including any diversification penalty
+	Energy      float64 // energy of Current
+	Proposal    float64 // energy of Proposed, excluding any diversification penalty
+	Best        float64 // energy of the best State encountered so far
+	Current     State
+	Proposed    State
+}
+
+// decide reports whether the run c should adopt the proposal p, whose energy including any penalty is f.
+// A decision is replayed from a decision log if one is being replayed, and recorded in one if one is being written.
+func (a *annealer) decide(c *chain, p proposal, f float64) (bool, error) {
+	if a.replay != nil {
+		return a.replay.decide(p.e)
+	}
+	ok, err := a.choice(c, p, f)
+	if err == nil && a.record != nil {
+		a.record.decision(p.e, ok)
+	}
+	return ok, err
+}
+
+// choice makes the decision described for decide.
+func (a *annealer) choice(c *chain, p proposal, f float64) (bool, error) {
+	dE := delta(f, c.f)
+	if a.acceptor == nil {
+		if math.Abs(dE) < a.plateau {
+			return a.plateauAccept == 0 || a.rand() < a.plateauAccept, nil
+		}
+		return dE < 0 || a.accept(dE, c.i), nil
+	}
+	return a.acceptor.Accept(Decision{
+		Run:         a.runs - 1,
+		Iter:        c.i,
+		Temperature: a.temp(c.i),
+		Delta:       dE,
+		Energy:      c.e,
+		Proposal:    p.e,
+		Best:        a.ebest,
+		Current:     c.s,
+		Proposed:    p.s,
+	})
+}
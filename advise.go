@@ -0,0 +1,93 @@
+package anneal
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// MeasureCost returns the mean time to propose a neighbor of s and compute its energy, measured over n proposals.
+// Proposals are not adopted, so s is unchanged except by any side effects of its own methods.
+func MeasureCost(s State, n int) (time.Duration, error) {
+	if n < 1 {
+		n = 1
+	}
+	start := time.Now()
+	for i := 0; i < n; i++ {
+		if p := evaluate(s, 1, -1, 1); p.err != nil {
+			return 0, p.err
+		}
+	}
+	return time.Since(start) / time.Duration(n), nil
+}
+
+// Advice describes whether a Schedule can complete within a wall-clock budget.
+type Advice struct {
+	Estimate time.Duration // estimated time to complete the Schedule
+	Fits     bool          // whether Estimate is within the budget
+
+	// Plans lists, for each number of workers up to the number of CPUs,
+	// the largest number of iterations per run that fits the budget.
+	Plans []Plan
+}
+
+// A Plan is a combination of Schedule parameters that fits a budget.
+type Plan struct {
+	Iter     int
+	Workers  int
+	Estimate time.Duration
+}
+
+func (a Advice) String() string {
+	var b strings.Builder
+	if a.Fits {
+		fmt.Fprintf(&b, "estimated %v: within budget", a.Estimate)
+	} else {
+		fmt.Fprintf(&b, "estimated %v: exceeds budget", a.Estimate)
+	}
+	for _, p := range a.Plans {
+		fmt.Fprintf(&b, "\n  Iter %d, Workers %d: estimated %v", p.Iter, p.Workers, p.Estimate)
+	}
+	return b.String()
+}
+
+// Advise estimates the time to complete sch if each proposal costs cost, as measured by MeasureCost,
+// and suggests Plans that fit budget.
+// A timed Schedule takes Duration per run regardless of cost, and its Plans convert the budget to iterations.
+//
+// Concurrent workers evaluate proposals in batches, but a batch is cut short when one of its proposals is adopted,
+// so the estimate assumes a speedup of the number of workers, limited by GOMAXPROCS,
+// and is optimistic at high temperatures, where most proposals are adopted.
+// The final systematic scan, if any, is not included.
+func (sch *Schedule) Advise(cost, budget time.Duration) Advice {
+	runs := sch.Restarts + 1
+	var a Advice
+	if sch.Duration > 0 {
+		a.Estimate = time.Duration(runs) * sch.Duration
+	} else {
+		a.Estimate = estimate(sch.Iter, runs, max(sch.Workers, 1), cost)
+	}
+	a.Fits = a.Estimate <= budget
+	if cost <= 0 {
+		return a
+	}
+	procs := runtime.GOMAXPROCS(0)
+	for w := 1; ; w *= 2 {
+		w = min(w, procs)
+		iter := int(float64(budget) / float64(runs) / float64(cost) * float64(w))
+		if iter > 0 {
+			a.Plans = append(a.Plans, Plan{iter, w, estimate(iter, runs, w, cost)})
+		}
+		if w == procs {
+			break
+		}
+	}
+	return a
+}
+
+// estimate returns the time to complete runs runs of iter iterations on w workers if each proposal costs cost.
+func estimate(iter, runs, w int, cost time.Duration) time.Duration {
+	speedup := min(w, runtime.GOMAXPROCS(0))
+	return time.Duration(float64(iter) * float64(runs) * float64(cost) / float64(speedup))
+}
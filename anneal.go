@@ -25,8 +25,9 @@ The quality of the result depends on the following conditions:
 package anneal
 
 import (
-	"math"
+	"io"
 	"math/rand"
+	"time"
 )
 
 // A State can undergo simulated annealing optimization.
@@ -36,52 +37,266 @@ type State interface {
 	Energy() float64
 
 	// Neighbor returns a State in the state space chosen randomly from those adjacent to the current State.
-	// Distinct States must not share memory. For example, do not reuse a slice from one State to a neighbor.
+	// Distinct States must not share memory, except as allowed for a Cloner.
+	// For example, do not reuse a slice from one State to a neighbor.
 	Neighbor() State
 }
 
+// A Tempered is a State whose proposals depend on the annealing temperature,
+// so that it can make large moves while the temperature is high and fine moves near the end of the schedule.
+// Anneal calls NeighborT in place of Neighbor when it is available.
+type Tempered interface {
+	State
+
+	// NeighborT returns a neighbor of the State proposed at the absolute temperature T,
+	// which is Schedule.Ti times the magnitude of the input State's energy at the start of each run.
+	NeighborT(T float64) State
+}
+
+// An AcceptNotifier is a State that maintains auxiliary data, such as position indices or cached partial sums,
+// that must be updated only when a move is adopted and not whenever a neighbor is proposed.
+type AcceptNotifier interface {
+	State
+
+	// OnAccept is called on next when Anneal adopts it in place of the current State prev.
+	// Neighbors that are rejected are never notified.
+	// When a restart resumes from the best State, that State is notified in place of the final State of the previous run.
+	OnAccept(prev, next State)
+}
+
 // A Schedule controls the annealing process.
 type Schedule struct {
 	Iter int     // number of iterations
 	Ti   float64 // initial temperature, as a multiple of the input State's energy
 	Tf   float64 // final temperature, as a multiple of the input State's energy
+
+	// Duration, if positive, is the length of each run as measured by Clock. It overrides Iter:
+	// the temperature decays as a function of the time elapsed rather than of the iteration index.
+	Duration time.Duration
+
+	// Cooling is the law by which the temperature decays, and CoolRate its rate, or 0 for the rate
+	// at which the temperature reaches Tf at the end of each run; see Cooling.
+	Cooling  Cooling
+	CoolRate float64
+
+	// Block, if greater than 1, is the number of iterations at each temperature level: the temperature
+	// is held constant for blocks of Block iterations, and the law of Cooling advances once per block,
+	// as in schedules specified by a number of Metropolis sweeps per level. A sweep of a State of n variables
+	// is typically n iterations. Block requires runs measured in iterations rather than Duration.
+	Block int
+
+	Restarts  int     // number of additional runs of the Schedule, each starting from the best State found so far
+	Diversify float64 // weight of the long-term memory penalty applied during restarts; see Componenter
+
+	// Shared, if not nil, holds the best State of cooperating searches that run concurrently with the same Shared.
+	// The search offers its best State to Shared whenever it improves, and adopts the State of Shared
+	// if it is better than its own best State at the start of each restart without Initializers
+	// and, if Exchange is positive, every Exchange iterations, where it replaces the current State of the run.
+	// Exchanges make the search depend on the timing of the others, so Shared excludes Seed.
+	Shared   *Shared
+	Exchange int
+
+	// Initializers, if not empty, construct the starting States of restarts, which are allocated among them
+	// in proportion to how often each has improved on the best State; see Initializer.
+	Initializers []Initializer
+
+	// Rate, if positive, limits the search to Rate energy evaluations per second on average,
+	// as measured by the system clock, by sleeping whenever it runs ahead, and Yield, if positive, makes it yield
+	// the processor to other goroutines every Yield evaluations, so that a search can run as a background task
+	// of a latency-sensitive service or game without starving other goroutines when GOMAXPROCS is 1.
+	Rate  float64
+	Yield int
+
+	Workers int // number of goroutines proposing and evaluating neighbors concurrently; see Anneal
+
+	Scan float64 // temperature, as a multiple of the input State's energy, below which to scan neighborhoods systematically; see Enumerator
+
+	Audit int // interval in iterations at which to check the current State's energy against a full recomputation; see Recomputer
+
+	// AcceptTarget, if positive, is the fraction of proposals to adopt, toward which Anneal steers the size
+	// of the proposals of States that implement Scaler, adjusting it every ScaleWindow iterations, or every 100 if ScaleWindow is zero.
+	AcceptTarget float64
+	ScaleWindow  int
+
+	// Samples and MaxSamples support noisy energy functions, such as Monte Carlo estimates.
+	// If either exceeds 1, the energy of each proposal is estimated by the mean of several evaluations:
+	// Samples at the start of each run, growing with the logarithm of the temperature to MaxSamples at its end,
+	// so that comparisons become more precise as the temperature falls and smaller differences matter.
+	// Systematic scans and the finishing descent use MaxSamples. Values less than 1 mean 1,
+	// and MaxSamples less than Samples means Samples.
+	// Each State adopted keeps the estimate made when it was proposed, which tends to be optimistic for the best State.
+	Samples    int
+	MaxSamples int
+
+	// Evals, if positive, limits the total number of energy evaluations, counting the input State,
+	// every proposal whether or not it is adopted, and every neighbor evaluated by scans and the finishing descent.
+	// The search ends when the budget is spent, even partway through a run, so that searches that evaluate
+	// several neighbors per iteration can be compared with sequential ones at equal cost.
+	Evals int
+
+	// If UseTarget is true, the search stops as soon as it finds a State of energy Target or less,
+	// as in constraint satisfaction problems, where a State of known optimal energy such as zero is a perfect solution.
+	Target    float64
+	UseTarget bool
+
+	// Landscape, if not nil and the input State implements Hasher, receives a log of every State evaluated,
+	// with its energy, the State from which it was proposed, and whether it was adopted, for offline analysis;
+	// see LandscapeHeader and package landscape. A write error is reported when the search ends.
+	Landscape io.Writer
+
+	// Decisions, if not nil, receives a log of every random decision of the search, with the energies of
+	// the proposals decided, from which Replay can reproduce the search. A write error is reported when the search ends.
+	Decisions io.Writer
+
+	// LocalSearch, if not nil, is a problem-specific improvement procedure, such as 2-opt or Lin–Kernighan moves
+	// for a tour, with which Anneal hybridizes the search in the manner of memetic algorithms: it applies the procedure
+	// to the current State every LocalEvery iterations if LocalEvery is positive, and otherwise whenever it adopts
+	// a proposal that becomes the best State, and adopts the result if its energy is lower.
+	// LocalBudget, or 1000 if it is zero, bounds the effort of each application; see LocalSearch.
+	LocalSearch LocalSearch
+	LocalEvery  int
+	LocalBudget int
+
+	// Polish, if positive, adds a finishing descent after the last run: starting from the best State,
+	// Anneal adopts sampled neighbors only if they improve on the current energy,
+	// and stops when Polish consecutive samples fail to do so.
+	Polish int
+
+	// History, if positive and the input State implements Snapshotter, is the number of most recently adopted States
+	// to record, encoded by their MarshalBinary methods, and report in Result.History, so that the trajectory of a search
+	// whose result is unexpectedly poor can be inspected. Recording costs an encoding for every State adopted.
+	History int
+
+	Recent int // number of recently seen States to remember in order to detect duplicate proposals; see Hasher
+
+	// Keep is the number of best distinct States to retain and report in Result.Elite.
+	// States that implement Hasher are distinguished by their hashes, and others by Equal if it is not nil.
+	Keep  int
+	Equal func(a, b State) bool
+
+	Observer Observer // recipient of progress reports, or nil
+	Every    int      // interval in iterations between progress reports; values less than 1 mean 1
+
+	// MaxOverhead, if positive, is the largest fraction of each run's time to spend in the Observer.
+	// Whenever reporting exceeds it, the interval between reports is doubled,
+	// and when reporting costs less than a quarter of it, the interval is halved again, but never below Every.
+	MaxOverhead float64
+
+	// Plateau, if positive, is a tolerance below which energy differences are treated as zero:
+	// a proposal whose energy differs from the current energy by less than Plateau in either direction
+	// is adopted with probability PlateauAccept, or always if PlateauAccept is zero,
+	// which helps the search traverse large flat regions of the landscape. An Acceptor overrides it.
+	Plateau       float64
+	PlateauAccept float64
+
+	// Better, if not nil, determines the best State in place of comparing energies:
+	// it reports whether s, whose energy is e, is better than best, whose energy is ebest.
+	// It might prefer feasible States or break ties by a secondary objective.
+	// It affects only which State Anneal returns, not which States it adopts during the search.
+	Better func(s State, e float64, best State, ebest float64) bool
+
+	// AcceptProb, if not nil, replaces the Metropolis criterion: a State whose energy exceeds
+	// the current energy by dE at temperature T is adopted with probability AcceptProb(dE, T) instead of exp(-dE/T).
+	// States of lower energy are always adopted.
+	AcceptProb func(dE, T float64) float64
+
+	NonFinite NonFinite // policy for energies that are NaN or infinite
+
+	Policy Policy // chooser of operators for States that implement Mover, or nil for a Bandit
+
+	Acceptor Acceptor // oracle deciding whether to adopt each proposal, overriding AcceptProb, or nil
+
+	// Labels are arbitrary key/value pairs, such as a team or experiment name, that identify the search.
+	// They are attached to every Progress report, to the Result, to the variables published by Metrics,
+	// and to elite pools written by WriteLabeledElite. They do not affect the search.
+	Labels map[string]string
+
+	Tracer Tracer // recipient of a span for the search, or nil; see Span
+
+	Clock Clock // source of the current time, or nil to use the system clock
+
+	Rand *rand.Rand // source of randomness for acceptance decisions, or nil to use the global source
+
+	// If UseSeed is true, the search draws its random decisions from a source seeded by Seed in place of Rand,
+	// and provides a second, independent source seeded by Seed to the input State and the States of Initializers
+	// if they implement Seeded. Searches from identical input States with identical Schedules, including Seed,
+	// then make identical decisions and return identical States and energies, provided that the States draw
+	// all their randomness from the sources they are given and that runs are measured in iterations rather than Duration,
+	// which suits golden-file tests of models. UseSeed requires Workers of at most 1 and excludes Rand.
+	Seed    int64
+	UseSeed bool
 }
 
 // NewSchedule returns a pointer to a Schedule populated with default values.
 func NewSchedule() *Schedule {
 	return &Schedule{
-		Iter: 1e6,
-		Ti:   1,
-		Tf:   1e-5,
+		Iter:  1e6,
+		Ti:    1,
+		Tf:    1e-5,
+		Every: 1000,
 	}
 }
 
 // Anneal implements simulated annealing on the input State and returns the best State encountered during the search.
+// The options configure a Schedule, starting from the defaults returned by NewSchedule.
+// A *Schedule is itself an Option that sets every field, so Anneal(s, sch) anneals according to sch.
+//
 // Once per iteration, it calls s.Neighbor() and then calls Energy() on the neighboring State.
 // The new State is adopted with probability 1 if its energy E' is lower than the original State's energy E,
 // and with probability exp(-(E'-E)/T) otherwise, where T = Ti * exp(-i/k) is the annealing temperature of the current iteration i,
 // and the scale factor k = Iter / ln(Ti/Tf) is the number of iterations required for the temperature to drop by a factor of e.
-func Anneal(s State, sch *Schedule) State {
-	e := s.Energy()
-	sbest, ebest := s, e
-	var (
-		T0 = e * sch.Ti
-		k  = float64(sch.Iter) / math.Log(sch.Ti/sch.Tf)
-	)
-	for i := 0; i < sch.Iter; i++ {
-		snew := s.Neighbor()
-		enew := snew.Energy()
-		if enew < e {
-			if enew < ebest {
-				sbest, ebest = snew, enew
-			}
-		} else {
-			T := T0 * math.Exp(-float64(i)/k)
-			if p := math.Exp(-(enew - e) / T); rand.Float64() > p {
-				continue
-			}
-		}
-		s, e = snew, enew
+// Temperatures are measured in units of the magnitude of the input State's energy, so energies may be negative.
+// If Schedule.Duration is positive, i is instead the time elapsed since the start of the run and k = Duration / ln(Ti/Tf),
+// so that the run fits a fixed latency budget regardless of how long each iteration takes.
+// Schedule.Cooling selects another law of decay; see Cooling.
+// If Schedule.Block is greater than 1, the temperature is held constant for blocks of Block iterations,
+// and i is rounded down to the start of its block.
+//
+// If Schedule.Restarts is positive, the Schedule is repeated that many more times, each time starting from the best State
+// encountered so far. If additionally Schedule.Diversify is nonzero and s implements Componenter,
+// energies during restarts are penalized according to the frequency of their components in previously adopted States.
+// If Schedule.Initializers is not empty, each restart instead starts from a new State constructed by one of them:
+// each is used once in turn, and then they are chosen at random in proportion to their smoothed rates of
+// improving on the best State, which Run reports in Result.Initializers.
+//
+// If Schedule.Workers is greater than 1, Anneal speculatively proposes and evaluates that many neighbors of the current State
+// at once, each on its own goroutine, so Neighbor and Energy must be safe to call concurrently.
+// The neighbors are then considered in turn, each consuming one iteration, as though they had been proposed sequentially.
+// When one is adopted, the rest are discarded, except that every evaluated neighbor is a candidate for the best State.
+// Because rejected proposals do not change the current State, the sequence of adopted States follows the same law
+// as in a sequential run. Parallel evaluation therefore pays off when Energy is expensive and most proposals are rejected.
+//
+// If Schedule.Scan is positive and s implements Enumerator, each run ends when the temperature falls below Scan times the energy of s.
+// Anneal then returns to the best State and descends systematically: it adopts the first neighbor from the State's
+// Neighborhood with lower energy and repeats until a complete scan finds none, so that the best State is a verified local optimum.
+// Run reports the verification in Result.Certificate.
+//
+// If Schedule.Polish is positive, Anneal finally returns to the best State and descends greedily,
+// proposing neighbors at the final temperature and adopting only improvements, until Polish consecutive proposals fail to improve.
+// A run often ends a move or two away from a better local optimum that this finds cheaply.
+//
+// Energies that are NaN or infinite are handled according to Schedule.NonFinite.
+// By default, proposals with such energies are rejected, and the input State must have a finite energy.
+//
+// If s implements FallibleEnergy or FallibleNeighbor, Anneal calls EnergyErr or NeighborErr in place of
+// Energy or Neighbor. The first error they return ends the search, and Anneal returns it
+// together with the best State encountered until then.
+func Anneal(s State, opts ...Option) (State, error) {
+	r, err := Run(s, opts...)
+	return r.Best, err
+}
+
+// Run anneals s as described for Anneal and returns a Result describing the run.
+// It returns an error without annealing if the Schedule is not valid.
+// If an error ends the search, the Result describes the search until then.
+// To drive the search step by step instead, use New.
+func Run(s State, opts ...Option) (Result, error) {
+	an, err := New(s, opts...)
+	if err != nil {
+		return Result{}, err
+	}
+	defer an.Close()
+	for an.Step() {
 	}
-	return sbest
+	return an.Result(), an.Err()
 }
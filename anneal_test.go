@@ -0,0 +1,12 @@
+package anneal
+
+import "testing"
+
+func TestSelfTest(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping SelfTest in short mode")
+	}
+	if err := SelfTest(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,47 @@
+package anneal
+
+import (
+	"fmt"
+	"math"
+)
+
+// A Recomputer is a State whose Energy is maintained incrementally,
+// for example by adding the change due to the move that produced it to the energy of its predecessor,
+// and that can also compute its energy from scratch.
+// When Schedule.Audit is positive, Anneal periodically compares the two to detect errors in the incremental computation.
+type Recomputer interface {
+	State
+
+	// RecomputeEnergy returns the energy of the State computed without reference to any cached or incremental value.
+	RecomputeEnergy() float64
+}
+
+// auditTol is the relative difference between incremental and recomputed energies
+// above which an audit reports drift.
+const auditTol = 1e-9
+
+// A Drift records a discrepancy between the energy of a State as reported by Energy and by RecomputeEnergy.
+// Because energies are audited at intervals, the error was introduced by a move adopted
+// at some iteration after the previous audit and no later than Iter.
+type Drift struct {
+	Run        int     // index of the run, counting restarts, in which the drift was detected
+	Iter       int     // iteration of the run at which the drift was detected
+	Energy     float64 // energy reported by Energy
+	Recomputed float64 // energy reported by RecomputeEnergy
+}
+
+func (d *Drift) String() string {
+	return fmt.Sprintf("energy drift in run %d at iteration %d: %v, recomputed %v", d.Run, d.Iter, d.Energy, d.Recomputed)
+}
+
+// check audits the energy e of the current State s at iteration i and records the first discrepancy.
+func (a *annealer) check(s State, e float64, i int) {
+	r, ok := s.(Recomputer)
+	if !ok || a.drift != nil {
+		return
+	}
+	re := r.RecomputeEnergy()
+	if d := math.Abs(e - re); d > auditTol*math.Max(1, math.Abs(re)) || math.IsNaN(d) {
+		a.drift = &Drift{Run: a.runs - 1, Iter: i, Energy: e, Recomputed: re}
+	}
+}
@@ -0,0 +1,104 @@
+package anneal
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/dkmccandless/anneal/core"
+)
+
+// The engine benchmarks anneal the same States with Anneal and core.Anneal
+// to measure the cost of the interface engine and its options over the bare generic loop.
+
+const benchIter = 10000
+
+// A well is a small State: a position on the integers with a quadratic energy.
+type well int
+
+func (w well) Energy() float64 { return float64(w * w) }
+
+func (w well) Neighbor() well { return w + well(2*rand.Intn(2)-1) }
+
+// A wellState adapts a well to State.
+type wellState struct{ well }
+
+func (w wellState) Neighbor() State { return wellState{w.well.Neighbor()} }
+
+// A ring is a large State: a ring of spins whose energy counts equal adjacent pairs.
+// Neighbor copies the spins and updates the energy incrementally.
+type ring struct {
+	spin []bool
+	e    int
+}
+
+func newRing(n int) *ring {
+	c := &ring{spin: make([]bool, n)}
+	for i := range c.spin {
+		c.spin[i] = rand.Intn(2) == 0
+	}
+	for i := range c.spin {
+		if c.spin[i] == c.spin[(i+1)%n] {
+			c.e++
+		}
+	}
+	return c
+}
+
+func (c *ring) Energy() float64 { return float64(c.e) }
+
+func (c *ring) Neighbor() *ring {
+	n := len(c.spin)
+	i := rand.Intn(n)
+	next := &ring{spin: append([]bool(nil), c.spin...), e: c.e}
+	for _, j := range [2]int{(i + n - 1) % n, (i + 1) % n} {
+		if c.spin[i] == c.spin[j] {
+			next.e--
+		} else {
+			next.e++
+		}
+	}
+	next.spin[i] = !next.spin[i]
+	return next
+}
+
+// A ringState adapts a ring to State.
+type ringState struct{ *ring }
+
+func (c ringState) Neighbor() State { return ringState{c.ring.Neighbor()} }
+
+func BenchmarkEngines(b *testing.B) {
+	sch := core.Schedule{Iter: benchIter, Ti: 10, Tf: 0.01}
+	opts := []Option{WithIterations(sch.Iter), WithTemperatures(sch.Ti, sch.Tf)}
+	b.Run("small/anneal", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			if _, err := Anneal(wellState{100}, opts...); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("small/core", func(b *testing.B) {
+		x := core.XorShift(1)
+		b.ReportAllocs()
+		for b.Loop() {
+			core.Anneal(well(100), sch, &x)
+		}
+	})
+	b.Run("large/anneal", func(b *testing.B) {
+		c := newRing(1000)
+		b.ReportAllocs()
+		for b.Loop() {
+			if _, err := Anneal(ringState{c}, opts...); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("large/core", func(b *testing.B) {
+		c := newRing(1000)
+		x := core.XorShift(1)
+		b.ReportAllocs()
+		for b.Loop() {
+			core.Anneal(c, sch, &x)
+		}
+	})
+}
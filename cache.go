@@ -0,0 +1,183 @@
+package anneal
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"maps"
+	"sync"
+	"time"
+)
+
+// A Cache stores the Results of annealing runs keyed by a fingerprint of the problem and by the Schedule,
+// so that a program answering repeated submissions of the same problem need not recompute them.
+// Only the Schedule fields that affect the search or the contents of its Result form part of the key; observers, workers,
+// and sources of randomness do not. Fields holding functions or interfaces, such as AcceptProb and Initializers, cannot be compared,
+// so they are not part of the key either; a Cache should not be shared by Schedules that differ in them.
+// Labels are not part of the key either: a cached Result carries the Labels of the call that returns it.
+// Because annealing is randomized, a cached Result is one sample of the outcome and not the only possible one.
+//
+// Cached Results are shared by every caller that receives them, so their States must not be modified.
+// A Cache is safe for concurrent use, and concurrent requests for the same key share a single run.
+type Cache struct {
+	mu      sync.Mutex
+	results map[cacheKey]*cacheEntry
+}
+
+type cacheKey struct {
+	fingerprint string
+	schedule    scheduleKey
+}
+
+// A cacheEntry is a Result that is complete once done is closed.
+type cacheEntry struct {
+	done chan struct{}
+	r    Result
+	err  error
+}
+
+// scheduleKey holds the Schedule fields that affect the search or its Result.
+type scheduleKey struct {
+	iter      int
+	ti, tf    float64
+	duration  time.Duration
+	cooling   Cooling
+	coolRate  float64
+	block     int
+	restarts  int
+	diversify float64
+	exchange  int
+	scan      float64
+	scaling   [2]float64
+	evals     int
+	local     [2]int
+	polish    int
+	keep      int
+	history   int
+	recent    int
+	audit     int
+	nonFinite NonFinite
+	plateau   [2]float64
+	samples   [2]int
+	target    float64
+	useTarget bool
+	seed      int64
+	useSeed   bool
+}
+
+func (sch *Schedule) key() scheduleKey {
+	return scheduleKey{
+		iter:      sch.Iter,
+		ti:        sch.Ti,
+		tf:        sch.Tf,
+		duration:  sch.Duration,
+		cooling:   sch.Cooling,
+		coolRate:  sch.CoolRate,
+		block:     sch.Block,
+		restarts:  sch.Restarts,
+		diversify: sch.Diversify,
+		exchange:  sch.Exchange,
+		scan:      sch.Scan,
+		scaling:   [2]float64{sch.AcceptTarget, float64(sch.ScaleWindow)},
+		evals:     sch.Evals,
+		local:     [2]int{sch.LocalEvery, sch.LocalBudget},
+		polish:    sch.Polish,
+		keep:      sch.Keep,
+		history:   sch.History,
+		recent:    sch.Recent,
+		audit:     sch.Audit,
+		nonFinite: sch.NonFinite,
+		plateau:   [2]float64{sch.Plateau, sch.PlateauAccept},
+		samples:   [2]int{sch.Samples, sch.MaxSamples},
+		target:    sch.Target,
+		useTarget: sch.UseTarget,
+		seed:      sch.Seed,
+		useSeed:   sch.UseSeed,
+	}
+}
+
+// NewCache returns an empty Cache.
+func NewCache() *Cache {
+	return &Cache{results: make(map[cacheKey]*cacheEntry)}
+}
+
+// Fingerprint returns a string identifying a problem by the SHA-256 digest of its description.
+func Fingerprint(description ...[]byte) string {
+	h := sha256.New()
+	for _, d := range description {
+		h.Write(d)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Run returns the cached Result for the problem identified by fingerprint under the configured Schedule,
+// or else anneals s as Run does and caches the Result. Results of runs that return an error are not cached.
+// The caller is responsible for ensuring that s belongs to the problem that fingerprint identifies.
+func (c *Cache) Run(fingerprint string, s State, opts ...Option) (Result, error) {
+	sch := configure(opts)
+	key := cacheKey{fingerprint, sch.key()}
+	c.mu.Lock()
+	if e, ok := c.results[key]; ok {
+		c.mu.Unlock()
+		<-e.done
+		if e.err == nil {
+			r := e.r
+			r.Labels = maps.Clone(sch.Labels)
+			return r, nil
+		}
+		// The run failed; try again.
+		return c.Run(fingerprint, s, opts...)
+	}
+	e := &cacheEntry{done: make(chan struct{})}
+	c.results[key] = e
+	c.mu.Unlock()
+	return c.fill(key, e, s, opts)
+}
+
+// Refresh bypasses the cache: it anneals s unconditionally and replaces any cached Result for the same key.
+func (c *Cache) Refresh(fingerprint string, s State, opts ...Option) (Result, error) {
+	key := cacheKey{fingerprint, configure(opts).key()}
+	e := &cacheEntry{done: make(chan struct{})}
+	c.mu.Lock()
+	c.results[key] = e
+	c.mu.Unlock()
+	return c.fill(key, e, s, opts)
+}
+
+// fill runs the annealer and completes e, removing it from the cache if the run fails.
+func (c *Cache) fill(key cacheKey, e *cacheEntry, s State, opts []Option) (Result, error) {
+	defer close(e.done)
+	e.err = errRunPanicked
+	defer func() {
+		if e.err != nil {
+			c.mu.Lock()
+			if c.results[key] == e {
+				delete(c.results, key)
+			}
+			c.mu.Unlock()
+		}
+	}()
+	e.r, e.err = Run(s, opts...)
+	return e.r, e.err
+}
+
+// errRunPanicked is the error recorded for a run that panics, so that waiters do not return its incomplete Result.
+var errRunPanicked = errors.New("anneal: cached run panicked")
+
+// Forget removes all cached Results for the problem identified by fingerprint.
+func (c *Cache) Forget(fingerprint string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for k := range c.results {
+		if k.fingerprint == fingerprint {
+			delete(c.results, k)
+		}
+	}
+}
+
+// Len returns the number of cached Results, including those still being computed.
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.results)
+}
@@ -0,0 +1,56 @@
+package anneal
+
+import (
+	"encoding/binary"
+	"math/rand"
+	"testing"
+)
+
+// A point is a Hasher and Snapshotter on the integers with energy x^2 and neighbors x ± 1.
+type point struct{ x int64 }
+
+func (p *point) Energy() float64 { return float64(p.x * p.x) }
+
+func (p *point) Neighbor() State { return &point{p.x + int64(2*rand.Intn(2)-1)} }
+
+func (p *point) Hash() uint64 { return uint64(p.x) }
+
+func (p *point) MarshalBinary() ([]byte, error) { return binary.AppendVarint(nil, p.x), nil }
+
+func (p *point) UnmarshalBinary(data []byte) error {
+	p.x, _ = binary.Varint(data)
+	return nil
+}
+
+func TestCacheKey(t *testing.T) {
+	c := NewCache()
+	base := []Option{WithIterations(100)}
+	r, err := c.Run("point", &point{5}, base...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.History != nil || r.Duplicates != nil {
+		t.Fatalf("Result without History or Recent has History %v, Duplicates %v", r.History, r.Duplicates)
+	}
+	// Options that add to the Result must not be answered from the entry of a Schedule without them.
+	r, err = c.Run("point", &point{5}, append(base, WithHistory(4))...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.History == nil {
+		t.Error("cached Result lacks the History of a Schedule with History")
+	}
+	r, err = c.Run("point", &point{5}, append(base, WithRecent(4))...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Duplicates == nil {
+		t.Error("cached Result lacks the Duplicates of a Schedule with Recent")
+	}
+	if _, err := c.Run("point", &point{5}, append(base, WithAudit(10))...); err != nil {
+		t.Fatal(err)
+	}
+	if n := c.Len(); n != 4 {
+		t.Errorf("Len = %d, want 4", n)
+	}
+}
@@ -0,0 +1,246 @@
+package anneal
+
+import (
+	"math"
+	"sort"
+)
+
+// Candidates holds, for each of n elements, a list of up to k other elements ordered by increasing distance.
+// Restricting moves to nearby elements lets Neighbor generate promising moves in constant time
+// on problems with very large neighborhoods, such as tours with many cities.
+// A Candidates is immutable after construction, so all States of a problem may share one.
+type Candidates struct {
+	k     int
+	idx   []int // idx[i*k : i*k+count[i]] lists the neighbors of element i
+	count []int // number of neighbors listed for each element
+}
+
+// NewCandidates returns the k nearest neighbors of each of n elements under the distance function dist.
+// It evaluates dist for every pair of elements, so it is suited to instances of moderate size;
+// use NewCandidatesXY for large sets of points in the plane.
+func NewCandidates(n, k int, dist func(i, j int) float64) *Candidates {
+	k = min(k, n-1)
+	c := newCandidates(n, k)
+	var h nearest
+	for i := 0; i < n; i++ {
+		h.reset(k)
+		for j := 0; j < n; j++ {
+			if j != i {
+				h.push(j, dist(i, j))
+			}
+		}
+		c.set(i, h.sorted())
+	}
+	return c
+}
+
+// NewCandidatesXY returns the k nearest neighbors of each point (xs[i], ys[i]) under Euclidean distance.
+// It buckets the points in a uniform grid and searches outward from each point's cell,
+// so construction takes time roughly proportional to n*k for evenly spread points.
+func NewCandidatesXY(xs, ys []float64, k int) *Candidates {
+	n := len(xs)
+	k = min(k, n-1)
+	c := newCandidates(n, k)
+	if k <= 0 {
+		return c
+	}
+	g := newGrid(xs, ys)
+	var h nearest
+	for i := 0; i < n; i++ {
+		h.reset(k)
+		cx, cy := g.cell(xs[i], ys[i])
+		for r := 0; ; r++ {
+			g.ring(cx, cy, r, func(j int) {
+				if j != i {
+					h.push(j, math.Hypot(xs[i]-xs[j], ys[i]-ys[j]))
+				}
+			})
+			// Points outside ring r are at least r cell widths away.
+			if h.full() && h.worst() <= float64(r)*g.size || r > g.nx+g.ny {
+				break
+			}
+		}
+		c.set(i, h.sorted())
+	}
+	return c
+}
+
+func newCandidates(n, k int) *Candidates {
+	k = max(k, 0)
+	return &Candidates{k: k, idx: make([]int, n*k), count: make([]int, n)}
+}
+
+func (c *Candidates) set(i int, near []int) {
+	c.count[i] = copy(c.idx[i*c.k:(i+1)*c.k], near)
+}
+
+// Len returns the number of elements.
+func (c *Candidates) Len() int { return len(c.count) }
+
+// Near returns the candidate neighbors of element i, nearest first.
+// The returned slice must not be modified.
+func (c *Candidates) Near(i int) []int {
+	return c.idx[i*c.k : i*c.k+c.count[i]]
+}
+
+// nearest accumulates the k elements of least distance seen so far.
+type nearest struct {
+	k    int
+	elem []int
+	dist []float64
+}
+
+func (h *nearest) reset(k int) {
+	h.k = k
+	h.elem, h.dist = h.elem[:0], h.dist[:0]
+}
+
+func (h *nearest) full() bool { return len(h.elem) == h.k }
+
+// worst returns the greatest distance retained.
+func (h *nearest) worst() float64 { return h.dist[len(h.dist)-1] }
+
+// push inserts j at distance d if it is among the k nearest, keeping the lists sorted.
+func (h *nearest) push(j int, d float64) {
+	if h.k == 0 || h.full() && d >= h.worst() {
+		return
+	}
+	pos := sort.SearchFloat64s(h.dist, d)
+	if !h.full() {
+		h.elem, h.dist = append(h.elem, 0), append(h.dist, 0)
+	}
+	copy(h.elem[pos+1:], h.elem[pos:])
+	copy(h.dist[pos+1:], h.dist[pos:])
+	h.elem[pos], h.dist[pos] = j, d
+}
+
+func (h *nearest) sorted() []int { return h.elem }
+
+// A grid buckets points into square cells.
+type grid struct {
+	x0, y0 float64
+	size   float64
+	nx, ny int
+	cells  [][]int
+}
+
+func newGrid(xs, ys []float64) *grid {
+	x0, x1 := minMax(xs)
+	y0, y1 := minMax(ys)
+	// Aim for about two points per cell.
+	w, h := x1-x0, y1-y0
+	size := math.Sqrt(2 * max(w*h, 1e-300) / float64(len(xs)))
+	if w == 0 || h == 0 {
+		size = 2 * max(w, h, 1e-300) / float64(len(xs))
+	}
+	g := &grid{
+		x0:   x0,
+		y0:   y0,
+		size: size,
+		nx:   int(w/size) + 1,
+		ny:   int(h/size) + 1,
+	}
+	g.cells = make([][]int, g.nx*g.ny)
+	for i := range xs {
+		cx, cy := g.cell(xs[i], ys[i])
+		g.cells[cy*g.nx+cx] = append(g.cells[cy*g.nx+cx], i)
+	}
+	return g
+}
+
+func (g *grid) cell(x, y float64) (int, int) {
+	cx := min(int((x-g.x0)/g.size), g.nx-1)
+	cy := min(int((y-g.y0)/g.size), g.ny-1)
+	return cx, cy
+}
+
+// ring calls f for each point in the cells at Chebyshev distance r from cell (cx, cy).
+func (g *grid) ring(cx, cy, r int, f func(int)) {
+	visit := func(x, y int) {
+		if x < 0 || y < 0 || x >= g.nx || y >= g.ny {
+			return
+		}
+		for _, j := range g.cells[y*g.nx+x] {
+			f(j)
+		}
+	}
+	if r == 0 {
+		visit(cx, cy)
+		return
+	}
+	for x := cx - r; x <= cx+r; x++ {
+		visit(x, cy-r)
+		visit(x, cy+r)
+	}
+	for y := cy - r + 1; y <= cy+r-1; y++ {
+		visit(cx-r, y)
+		visit(cx+r, y)
+	}
+}
+
+func minMax(v []float64) (lo, hi float64) {
+	if len(v) == 0 {
+		return 0, 0
+	}
+	lo, hi = v[0], v[0]
+	for _, x := range v[1:] {
+		lo, hi = min(lo, x), max(hi, x)
+	}
+	return lo, hi
+}
+
+// DontLook implements don't-look bits: a set of active elements around which
+// moves are worth trying, kept in a queue that yields them in turn.
+// Next puts the element it returns to sleep; a caller that finds an improving move around it,
+// or that adopts a move changing the surroundings of other elements, wakes them again.
+// Because it changes only when a move is adopted, a DontLook is typically maintained by an AcceptNotifier.
+type DontLook struct {
+	asleep []bool
+	queue  []int // active elements, in the order they will be returned
+	head   int
+}
+
+// NewDontLook returns a DontLook for n elements, all of which are initially active.
+func NewDontLook(n int) *DontLook {
+	d := &DontLook{asleep: make([]bool, n), queue: make([]int, n)}
+	for i := range d.queue {
+		d.queue[i] = i
+	}
+	return d
+}
+
+// Next removes the next active element from the queue, puts it to sleep, and returns it.
+// It returns false if no element is active.
+func (d *DontLook) Next() (int, bool) {
+	if d.head == len(d.queue) {
+		return 0, false
+	}
+	i := d.queue[d.head]
+	d.head++
+	if d.head > len(d.queue)/2 {
+		// Discard the returned elements, so that the queue never holds more than twice as many entries
+		// as there are active elements.
+		d.queue, d.head = d.queue[:copy(d.queue, d.queue[d.head:])], 0
+	}
+	d.asleep[i] = true
+	return i, true
+}
+
+// Wake activates element i and enqueues it if it was asleep.
+func (d *DontLook) Wake(i int) {
+	if d.asleep[i] {
+		d.asleep[i] = false
+		d.queue = append(d.queue, i)
+	}
+}
+
+// Active reports whether element i is active.
+func (d *DontLook) Active(i int) bool { return !d.asleep[i] }
+
+// Clone returns an independent copy of d.
+func (d *DontLook) Clone() *DontLook {
+	return &DontLook{
+		asleep: append([]bool(nil), d.asleep...),
+		queue:  append([]int(nil), d.queue[d.head:]...),
+	}
+}
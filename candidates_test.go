@@ -0,0 +1,152 @@
+package anneal
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// checkNearest reports an error unless c lists, for each of n elements, min(k, n-1) distinct other elements, nearest first,
+// whose distances are the least of those to all other elements. Among equidistant elements any may be chosen.
+func checkNearest(t *testing.T, c *Candidates, n, k int, dist func(i, j int) float64) {
+	t.Helper()
+	if c.Len() != n {
+		t.Fatalf("Len = %d, want %d", c.Len(), n)
+	}
+	for i := range n {
+		var all []float64
+		for j := range n {
+			if j != i {
+				all = append(all, dist(i, j))
+			}
+		}
+		slices.Sort(all)
+		want := all[:min(k, n-1)]
+		near := c.Near(i)
+		got := make([]float64, len(near))
+		for m, j := range near {
+			if j == i || j < 0 || j >= n || slices.Contains(near[:m], j) {
+				t.Fatalf("Near(%d) = %v, want distinct other elements", i, near)
+			}
+			got[m] = dist(i, j)
+		}
+		if !slices.Equal(got, want) {
+			t.Fatalf("Near(%d) = %v at distances %v, want distances %v", i, near, got, want)
+		}
+	}
+}
+
+func TestNewCandidates(t *testing.T) {
+	// Points on a line at unit spacing are equidistant from their neighbors on either side.
+	line := func(i, j int) float64 { return math.Abs(float64(i - j)) }
+	for _, test := range []struct {
+		name string
+		n, k int
+		dist func(i, j int) float64
+	}{
+		{"random", 50, 5, func() func(i, j int) float64 {
+			x := make([]float64, 50)
+			for i := range x {
+				x[i] = rand.Float64()
+			}
+			return func(i, j int) float64 { return math.Abs(x[i] - x[j]) }
+		}()},
+		{"ties", 20, 3, line},
+		{"k = n-1", 10, 9, line},
+		{"k > n", 10, 20, line},
+		{"k = 0", 10, 0, line},
+		{"one element", 1, 3, line},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			checkNearest(t, NewCandidates(test.n, test.k, test.dist), test.n, test.k, test.dist)
+		})
+	}
+}
+
+func TestNewCandidatesXY(t *testing.T) {
+	random := func(n int) (xs, ys []float64) {
+		xs, ys = make([]float64, n), make([]float64, n)
+		for i := range n {
+			xs[i], ys[i] = 1000*rand.Float64(), 1000*rand.Float64()
+		}
+		return xs, ys
+	}
+	// A square lattice has many equidistant points, and collinear and coincident points make degenerate grids.
+	var latticeX, latticeY []float64
+	for i := range 100 {
+		latticeX, latticeY = append(latticeX, float64(i%10)), append(latticeY, float64(i/10))
+	}
+	randomX, randomY := random(500)
+	clusterX, clusterY := random(100)
+	for i := range 50 {
+		clusterX[i], clusterY[i] = clusterX[i]/1e6, clusterY[i]/1e6
+	}
+	for _, test := range []struct {
+		name   string
+		xs, ys []float64
+		k      int
+	}{
+		{"random", randomX, randomY, 10},
+		{"clustered", clusterX, clusterY, 10},
+		{"lattice", latticeX, latticeY, 4},
+		{"lattice k = 6", latticeX, latticeY, 6},
+		{"collinear", []float64{0, 1, 2, 3, 4, 5}, []float64{0, 0, 0, 0, 0, 0}, 2},
+		{"coincident", []float64{1, 1, 1, 1}, []float64{2, 2, 2, 2}, 2},
+		{"k > n", latticeX[:5], latticeY[:5], 10},
+		{"k = 0", latticeX, latticeY, 0},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			dist := func(i, j int) float64 { return math.Hypot(test.xs[i]-test.xs[j], test.ys[i]-test.ys[j]) }
+			checkNearest(t, NewCandidatesXY(test.xs, test.ys, test.k), len(test.xs), test.k, dist)
+		})
+	}
+}
+
+func TestDontLook(t *testing.T) {
+	const n = 10
+	d := NewDontLook(n)
+	var order []int
+	for {
+		i, ok := d.Next()
+		if !ok {
+			break
+		}
+		order = append(order, i)
+	}
+	if len(order) != n {
+		t.Fatalf("Next returned %d elements, want %d", len(order), n)
+	}
+	for i, x := range order {
+		if x != i {
+			t.Fatalf("Next returned %v, want elements in order", order)
+		}
+	}
+
+	// Waking and taking elements indefinitely must not grow the queue beyond a bound.
+	active := make([]bool, n)
+	for range 100000 {
+		if rand.Intn(2) == 0 {
+			i := rand.Intn(n)
+			d.Wake(i)
+			active[i] = true
+			continue
+		}
+		i, ok := d.Next()
+		if !ok {
+			for j, a := range active {
+				if a {
+					t.Fatalf("Next returned false with element %d active", j)
+				}
+			}
+			continue
+		}
+		if !active[i] {
+			t.Fatalf("Next returned inactive element %d", i)
+		}
+		active[i] = false
+	}
+	if c := cap(d.queue); c > 4*n {
+		t.Errorf("queue capacity = %d, want at most %d", c, 4*n)
+	}
+}
@@ -0,0 +1,48 @@
+package anneal
+
+import (
+	"sync"
+	"time"
+)
+
+// A Clock is a source of the current time. Anneal reads it to time runs of fixed Duration,
+// to measure the overhead of reporting, and to compute the elapsed time reported in Progress,
+// so that a Clock under the caller's control makes these deterministic.
+// Resource usage in Result is always measured by the system clock.
+type Clock interface {
+	Now() time.Time
+}
+
+// systemClock is the Clock that reads the system time.
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// A ManualClock is a Clock whose time changes only when it is set or advanced,
+// for tests and for simulations that run the annealer in virtual time.
+// The zero ManualClock reads the zero time. A ManualClock is safe for concurrent use.
+type ManualClock struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+// Now returns the current time of c.
+func (c *ManualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.t
+}
+
+// Set sets the current time of c to t.
+func (c *ManualClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.t = t
+}
+
+// Advance moves the current time of c forward by d.
+func (c *ManualClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.t = c.t.Add(d)
+}
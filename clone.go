@@ -0,0 +1,24 @@
+package anneal
+
+// A Cloner is a State that can copy itself. States that implement Cloner may share memory
+// that adopting a later State modifies, contrary to the usual requirement of Neighbor:
+// for example, each State might be a lightweight view of a buffer that OnAccept updates in place
+// by applying the adopted move, so that no State need be copied when it is proposed.
+// Anneal retains a Clone of the input State and of each new best State, and returns one of them as the best State,
+// so that the result is independent of the search that produced it, at the cost of a copy per improvement
+// rather than per step. When the search resumes from the best State, for a restart, a systematic scan,
+// or the finishing descent, it resumes from another Clone of it. Other facilities that retain States, such as Schedule.Keep, retain them as they are.
+type Cloner interface {
+	State
+
+	// Clone returns a copy of the State that shares no memory that later moves could modify.
+	Clone() State
+}
+
+// keep returns s, or a copy of s if it is a Cloner, for retention as the best State.
+func keep(s State) State {
+	if c, ok := s.(Cloner); ok {
+		return c.Clone()
+	}
+	return s
+}
@@ -0,0 +1,383 @@
+// Command anneal searches for low-energy solutions of problems read from files.
+//
+// Usage:
+//
+//	anneal [flags] problem
+//
+// The problem file is a QUBO, a traveling salesman instance in the TSPLIB format, or a Go plugin,
+// as the -type flag says or, by default, as its extension suggests: .tsp for TSPLIB, .so for a plugin,
+// and anything else for a QUBO.
+//
+// A QUBO is either a square matrix of coefficients, one row per line, whose energy is x^T Q x as described
+// for qubo.FromMatrix, or a file in the qbsolv format: a line "p qubo 0 maxNodes nNodes nCouplers"
+// followed by lines "i j v" giving the linear (i == j) and quadratic terms. Lines beginning with "c" or "#" are comments.
+// The solution is written as a string of bits.
+//
+// A TSPLIB instance is read as described for package tsp, and the solution is written as a TSPLIB tour.
+//
+// A plugin, built with go build -buildmode=plugin, must export a function NewState of type
+// func() anneal.State or func() (anneal.State, error) returning the input State.
+// Its solution is written by its MarshalText method if it has one, by its String method if it has one,
+// and otherwise in the format %v.
+//
+// The flags are:
+//
+//	-type kind
+//		the kind of problem: qubo, tsp, or plugin
+//	-schedule file
+//		read the annealing Schedule from a JSON configuration file; see anneal.LoadSchedule
+//	-seed n
+//		seed the search's acceptance decisions for reproducibility; see anneal.Schedule.Seed
+//	-o file
+//		write the best solution to file rather than to the standard output
+//	-trace file
+//		write a CSV trace of the progress reports to file
+//	-plot file
+//		write an SVG plot of the progress reports to file; see package trace
+//	-decisions file
+//		write a decision log to file, from which anneal.Replay can reproduce the search
+//	-every n
+//		report progress every n iterations (default from the Schedule)
+//	-q
+//		do not report progress to the standard error
+//
+// An interrupt stops the search early, and the best solution found until then is written.
+package main
+
+import (
+	"bufio"
+	"encoding"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"plugin"
+	"strconv"
+	"strings"
+
+	"github.com/dkmccandless/anneal"
+	"github.com/dkmccandless/anneal/qubo"
+	"github.com/dkmccandless/anneal/trace"
+	"github.com/dkmccandless/anneal/tsp"
+)
+
+var (
+	kind      = flag.String("type", "", "kind of problem: qubo, tsp, or plugin (default from the file extension)")
+	schedule  = flag.String("schedule", "", "JSON configuration `file` of the annealing Schedule")
+	seed      = flag.Int64("seed", 0, "seed the search with `n` for reproducibility")
+	output    = flag.String("o", "", "write the best solution to `file`")
+	traceFile = flag.String("trace", "", "write a CSV trace of progress to `file`")
+	plot      = flag.String("plot", "", "write an SVG plot of progress to `file`")
+	decisions = flag.String("decisions", "", "write a decision log to `file`")
+	every     = flag.Int("every", 0, "report progress every `n` iterations")
+	quiet     = flag.Bool("q", false, "do not report progress")
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: anneal [flags] problem\n")
+	flag.PrintDefaults()
+	os.Exit(2)
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("anneal: ")
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() != 1 {
+		usage()
+	}
+	path := flag.Arg(0)
+
+	s, write, err := load(path, *kind)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	sch := anneal.NewSchedule()
+	if *schedule != "" {
+		f, err := os.Open(*schedule)
+		if err != nil {
+			log.Fatal(err)
+		}
+		sch, err = anneal.LoadSchedule(f)
+		f.Close()
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+	opts := []anneal.Option{sch}
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "seed":
+			opts = append(opts, anneal.WithSeed(*seed))
+		case "every":
+			sch.Every = *every
+		}
+	})
+
+	var obs observer
+	if !*quiet {
+		obs.progress = os.Stderr
+	}
+	if *traceFile != "" {
+		f, err := os.Create(*traceFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		obs.trace = bufio.NewWriter(f)
+		fmt.Fprintln(obs.trace, "run,iter,temperature,energy,best,accepted,elapsed")
+		defer obs.trace.Flush()
+	}
+	if *plot != "" {
+		obs.rec = new(trace.Recorder)
+	}
+	if obs.progress != nil || obs.trace != nil || obs.rec != nil {
+		opts = append(opts, anneal.WithObserver(&obs, sch.Every))
+	}
+	if *decisions != "" {
+		f, err := os.Create(*decisions)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		opts = append(opts, anneal.WithDecisions(f))
+	}
+
+	r, err := anneal.RunInterruptible(s, opts...)
+	if r.Best == nil {
+		log.Fatal(err)
+	}
+	if err != nil && !errors.Is(err, anneal.ErrInterrupted) {
+		log.Print(err)
+	}
+	if obs.rec != nil {
+		if err := writePlot(*plot, obs.rec.Reports()); err != nil {
+			log.Print(err)
+		}
+	}
+	if !*quiet {
+		fmt.Fprintf(os.Stderr, "best energy %v after %d evaluations in %v\n", r.Energy, r.Evaluations, r.Usage.Wall)
+	}
+
+	out := io.Writer(os.Stdout)
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		out = f
+	}
+	bw := bufio.NewWriter(out)
+	if err := write(bw, r.Best); err != nil {
+		log.Fatal(err)
+	}
+	if err := bw.Flush(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// An observer reports progress to a terminal and to trace files.
+type observer struct {
+	progress io.Writer       // or nil
+	trace    *bufio.Writer   // or nil
+	rec      *trace.Recorder // or nil
+}
+
+func (o *observer) Observe(p anneal.Progress) {
+	if o.progress != nil {
+		fmt.Fprintf(o.progress, "run %d iteration %d: T %.4g, energy %v, best %v\n", p.Run, p.Iter, p.Temperature, p.Energy, p.Best)
+	}
+	if o.trace != nil {
+		fmt.Fprintf(o.trace, "%d,%d,%v,%v,%v,%d,%v\n", p.Run, p.Iter, p.Temperature, p.Energy, p.Best, p.Accepted, p.Elapsed.Seconds())
+	}
+	if o.rec != nil {
+		o.rec.Observe(p)
+	}
+}
+
+// writePlot writes an SVG plot of reports to the named file.
+func writePlot(name string, reports []anneal.Progress) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	if err := trace.WriteSVG(f, reports); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// A writer writes a solution.
+type writer func(w io.Writer, s anneal.State) error
+
+// load reads the problem at path of the given kind and returns its input State and the writer of its solutions.
+func load(path, kind string) (anneal.State, writer, error) {
+	if kind == "" {
+		switch filepath.Ext(path) {
+		case ".tsp":
+			kind = "tsp"
+		case ".so":
+			kind = "plugin"
+		default:
+			kind = "qubo"
+		}
+	}
+	switch kind {
+	case "qubo":
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		defer f.Close()
+		p, err := parseQUBO(f)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return p.Random(), writeBits, nil
+	case "tsp":
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		defer f.Close()
+		in, err := tsp.Parse(f)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return in.RandomTour(), func(w io.Writer, s anneal.State) error { return writeTour(w, in, s.(*tsp.Tour)) }, nil
+	case "plugin":
+		s, err := loadPlugin(path)
+		return s, writeState, err
+	}
+	return nil, nil, fmt.Errorf("unknown problem type %q", kind)
+}
+
+// parseQUBO reads a QUBO as a square matrix or in the qbsolv format.
+func parseQUBO(r io.Reader) (*qubo.Problem, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 1<<24)
+	var (
+		rows  [][]float64
+		terms []qubo.Term
+		n     = -1 // number of bits of the qbsolv format, or -1 for a matrix
+	)
+	for line := 1; sc.Scan(); line++ {
+		f := strings.Fields(sc.Text())
+		if len(f) == 0 || f[0] == "c" || strings.HasPrefix(f[0], "#") {
+			continue
+		}
+		if f[0] == "p" {
+			if len(f) != 6 || f[1] != "qubo" || n >= 0 || rows != nil {
+				return nil, fmt.Errorf("line %d: invalid problem line", line)
+			}
+			var err error
+			if n, err = strconv.Atoi(f[3]); err != nil || n < 0 {
+				return nil, fmt.Errorf("line %d: invalid number of nodes %q", line, f[3])
+			}
+			continue
+		}
+		if n >= 0 {
+			if len(f) != 3 {
+				return nil, fmt.Errorf("line %d: want i j v", line)
+			}
+			i, erri := strconv.Atoi(f[0])
+			j, errj := strconv.Atoi(f[1])
+			v, errv := strconv.ParseFloat(f[2], 64)
+			if erri != nil || errj != nil || errv != nil || i < 0 || j < 0 || i >= n || j >= n {
+				return nil, fmt.Errorf("line %d: invalid term %q", line, sc.Text())
+			}
+			terms = append(terms, qubo.Term{I: i, J: j, V: v})
+			continue
+		}
+		row := make([]float64, len(f))
+		for k, s := range f {
+			v, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid coefficient %q", line, s)
+			}
+			row[k] = v
+		}
+		rows = append(rows, row)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if n >= 0 {
+		return qubo.New(n, terms), nil
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("empty QUBO")
+	}
+	for i, row := range rows {
+		if len(row) != len(rows) {
+			return nil, fmt.Errorf("row %d of the matrix has %d coefficients, want %d", i+1, len(row), len(rows))
+		}
+	}
+	return qubo.FromMatrix(rows), nil
+}
+
+// loadPlugin opens the plugin at path and returns the State constructed by its NewState function.
+func loadPlugin(path string) (anneal.State, error) {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	sym, err := p.Lookup("NewState")
+	if err != nil {
+		return nil, err
+	}
+	switch f := sym.(type) {
+	case func() anneal.State:
+		return f(), nil
+	case func() (anneal.State, error):
+		return f()
+	}
+	return nil, fmt.Errorf("%s: NewState has type %T, want func() anneal.State or func() (anneal.State, error)", path, sym)
+}
+
+func writeBits(w io.Writer, s anneal.State) error {
+	for _, b := range s.(*qubo.State).X {
+		c := byte('0')
+		if b {
+			c = '1'
+		}
+		if _, err := w.Write([]byte{c}); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintln(w)
+	return err
+}
+
+func writeTour(w io.Writer, in *tsp.Instance, t *tsp.Tour) error {
+	fmt.Fprintf(w, "NAME : %s.tour\nCOMMENT : Length %d\nTYPE : TOUR\nDIMENSION : %d\nTOUR_SECTION\n", in.Name, t.Length(), in.Len())
+	for _, c := range t.Order {
+		fmt.Fprintln(w, c+1)
+	}
+	_, err := fmt.Fprint(w, "-1\nEOF\n")
+	return err
+}
+
+func writeState(w io.Writer, s anneal.State) error {
+	switch s := s.(type) {
+	case encoding.TextMarshaler:
+		b, err := s.MarshalText()
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "%s\n", b)
+		return err
+	case fmt.Stringer:
+		_, err := fmt.Fprintln(w, s.String())
+		return err
+	}
+	_, err := fmt.Fprintf(w, "%v\n", s)
+	return err
+}
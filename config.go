@@ -0,0 +1,197 @@
+package anneal
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// A scheduleConfig is the JSON form of the Schedule fields that hold plain values.
+// Absent fields leave the corresponding Schedule fields unchanged when decoding.
+type scheduleConfig struct {
+	Iter          *int       `json:"iter,omitempty"`
+	Ti            *float64   `json:"ti,omitempty"`
+	Tf            *float64   `json:"tf,omitempty"`
+	Duration      *duration  `json:"duration,omitempty"`
+	Cooling       *Cooling   `json:"cooling,omitempty"`
+	CoolRate      *float64   `json:"cool_rate,omitempty"`
+	Block         *int       `json:"block,omitempty"`
+	Restarts      *int       `json:"restarts,omitempty"`
+	Diversify     *float64   `json:"diversify,omitempty"`
+	Exchange      *int       `json:"exchange,omitempty"`
+	Rate          *float64   `json:"rate,omitempty"`
+	Yield         *int       `json:"yield,omitempty"`
+	Workers       *int       `json:"workers,omitempty"`
+	Scan          *float64   `json:"scan,omitempty"`
+	Audit         *int       `json:"audit,omitempty"`
+	AcceptTarget  *float64   `json:"accept_target,omitempty"`
+	ScaleWindow   *int       `json:"scale_window,omitempty"`
+	Samples       *int       `json:"samples,omitempty"`
+	MaxSamples    *int       `json:"max_samples,omitempty"`
+	Evals         *int       `json:"evals,omitempty"`
+	Target        *float64   `json:"target,omitempty"`
+	LocalEvery    *int       `json:"local_every,omitempty"`
+	LocalBudget   *int       `json:"local_budget,omitempty"`
+	Polish        *int       `json:"polish,omitempty"`
+	History       *int       `json:"history,omitempty"`
+	Recent        *int       `json:"recent,omitempty"`
+	Keep          *int       `json:"keep,omitempty"`
+	Every         *int       `json:"every,omitempty"`
+	MaxOverhead   *float64   `json:"max_overhead,omitempty"`
+	Plateau       *float64   `json:"plateau,omitempty"`
+	PlateauAccept *float64   `json:"plateau_accept,omitempty"`
+	NonFinite     *NonFinite `json:"non_finite,omitempty"`
+	Seed          *int64     `json:"seed,omitempty"`
+
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// A duration is a time.Duration that is encoded in JSON as a string such as "1m30s".
+type duration time.Duration
+
+func (d duration) MarshalText() ([]byte, error) { return []byte(time.Duration(d).String()), nil }
+
+func (d *duration) UnmarshalText(text []byte) error {
+	v, err := time.ParseDuration(string(text))
+	*d = duration(v)
+	return err
+}
+
+// MarshalJSON encodes the Schedule fields that hold plain values, such as Iter and Ti, as a JSON object.
+// Fields holding functions, interfaces, or other objects, such as Observer and Rand, are omitted,
+// and so are Target unless UseTarget is true and Seed unless UseSeed is true.
+// Its receiver is a value, so that a Schedule encodes in the same way as a *Schedule.
+func (sch Schedule) MarshalJSON() ([]byte, error) {
+	d := duration(sch.Duration)
+	c := scheduleConfig{
+		Iter: &sch.Iter, Ti: &sch.Ti, Tf: &sch.Tf, Duration: &d,
+		Cooling: &sch.Cooling, CoolRate: &sch.CoolRate, Block: &sch.Block,
+		Restarts: &sch.Restarts, Diversify: &sch.Diversify, Exchange: &sch.Exchange,
+		Rate: &sch.Rate, Yield: &sch.Yield, Workers: &sch.Workers,
+		Scan: &sch.Scan, Audit: &sch.Audit, AcceptTarget: &sch.AcceptTarget, ScaleWindow: &sch.ScaleWindow,
+		Samples: &sch.Samples, MaxSamples: &sch.MaxSamples,
+		Evals: &sch.Evals, LocalEvery: &sch.LocalEvery, LocalBudget: &sch.LocalBudget,
+		Polish: &sch.Polish, History: &sch.History, Recent: &sch.Recent, Keep: &sch.Keep,
+		Every: &sch.Every, MaxOverhead: &sch.MaxOverhead,
+		Plateau: &sch.Plateau, PlateauAccept: &sch.PlateauAccept, NonFinite: &sch.NonFinite,
+		Labels: sch.Labels,
+	}
+	if sch.UseTarget {
+		c.Target = &sch.Target
+	}
+	if sch.UseSeed {
+		c.Seed = &sch.Seed
+	}
+	return json.Marshal(c)
+}
+
+// UnmarshalJSON decodes a JSON object as written by MarshalJSON into sch.
+// Fields absent from the object keep their values, so decoding into a Schedule returned by NewSchedule
+// overrides only the defaults that the object mentions. A target field sets UseTarget, and a seed field UseSeed.
+// Unknown fields are an error, so that misspellings do not pass unnoticed.
+func (sch *Schedule) UnmarshalJSON(data []byte) error {
+	var c scheduleConfig
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&c); err != nil {
+		return err
+	}
+	set(&sch.Iter, c.Iter)
+	set(&sch.Ti, c.Ti)
+	set(&sch.Tf, c.Tf)
+	if c.Duration != nil {
+		sch.Duration = time.Duration(*c.Duration)
+	}
+	set(&sch.Cooling, c.Cooling)
+	set(&sch.CoolRate, c.CoolRate)
+	set(&sch.Block, c.Block)
+	set(&sch.Restarts, c.Restarts)
+	set(&sch.Diversify, c.Diversify)
+	set(&sch.Exchange, c.Exchange)
+	set(&sch.Rate, c.Rate)
+	set(&sch.Yield, c.Yield)
+	set(&sch.Workers, c.Workers)
+	set(&sch.Scan, c.Scan)
+	set(&sch.Audit, c.Audit)
+	set(&sch.AcceptTarget, c.AcceptTarget)
+	set(&sch.ScaleWindow, c.ScaleWindow)
+	set(&sch.Samples, c.Samples)
+	set(&sch.MaxSamples, c.MaxSamples)
+	set(&sch.Evals, c.Evals)
+	if c.Target != nil {
+		sch.Target, sch.UseTarget = *c.Target, true
+	}
+	set(&sch.LocalEvery, c.LocalEvery)
+	set(&sch.LocalBudget, c.LocalBudget)
+	set(&sch.Polish, c.Polish)
+	set(&sch.History, c.History)
+	set(&sch.Recent, c.Recent)
+	set(&sch.Keep, c.Keep)
+	set(&sch.Every, c.Every)
+	set(&sch.MaxOverhead, c.MaxOverhead)
+	set(&sch.Plateau, c.Plateau)
+	set(&sch.PlateauAccept, c.PlateauAccept)
+	set(&sch.NonFinite, c.NonFinite)
+	if c.Seed != nil {
+		sch.Seed, sch.UseSeed = *c.Seed, true
+	}
+	if c.Labels != nil {
+		sch.Labels = c.Labels
+	}
+	return nil
+}
+
+// set assigns *v to *dst if v is not nil.
+func set[T any](dst *T, v *T) {
+	if v != nil {
+		*dst = *v
+	}
+}
+
+// LoadSchedule reads a JSON configuration from r, as described for Schedule.UnmarshalJSON,
+// and returns the Schedule it describes, with defaults from NewSchedule for the fields it omits.
+// It returns an error if the resulting Schedule is not valid.
+func LoadSchedule(r io.Reader) (*Schedule, error) {
+	sch := NewSchedule()
+	if err := json.NewDecoder(r).Decode(sch); err != nil {
+		return nil, fmt.Errorf("anneal: loading Schedule: %w", err)
+	}
+	if err := sch.Validate(); err != nil {
+		return nil, err
+	}
+	return sch, nil
+}
+
+var nonFiniteNames = [...]string{
+	RejectNonFinite: "reject",
+	AbortNonFinite:  "abort",
+	InfeasibleInf:   "infeasible",
+}
+
+func (p NonFinite) String() string {
+	if p >= 0 && int(p) < len(nonFiniteNames) {
+		return nonFiniteNames[p]
+	}
+	return fmt.Sprintf("NonFinite(%d)", int(p))
+}
+
+// MarshalText encodes p as one of "reject", "abort", or "infeasible".
+func (p NonFinite) MarshalText() ([]byte, error) {
+	if p < 0 || int(p) >= len(nonFiniteNames) {
+		return nil, fmt.Errorf("anneal: unknown NonFinite policy %d", int(p))
+	}
+	return []byte(nonFiniteNames[p]), nil
+}
+
+// UnmarshalText decodes a policy encoded by MarshalText.
+func (p *NonFinite) UnmarshalText(text []byte) error {
+	for i, name := range nonFiniteNames {
+		if string(text) == name {
+			*p = NonFinite(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("anneal: unknown NonFinite policy %q", text)
+}
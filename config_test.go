@@ -0,0 +1,102 @@
+package anneal
+
+import (
+	"bytes"
+	"encoding/json"
+	"maps"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestScheduleJSON(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		edit func(sch *Schedule)
+	}{
+		{"defaults", func(sch *Schedule) {}},
+		{"values", func(sch *Schedule) {
+			sch.Iter, sch.Ti, sch.Tf = 5000, 20, 0.5
+			sch.Cooling, sch.CoolRate = CoolGeometric, 0.99
+			sch.Restarts, sch.Diversify = 3, 0.25
+			sch.NonFinite = InfeasibleInf
+			sch.Labels = map[string]string{"model": "ising", "run": "7"}
+		}},
+		{"Duration", func(sch *Schedule) { sch.Duration = 90 * time.Second }},
+		{"Target", func(sch *Schedule) { sch.Target, sch.UseTarget = -12.5, true }},
+		{"zero Target", func(sch *Schedule) { sch.UseTarget = true }},
+		{"Seed", func(sch *Schedule) { sch.Seed, sch.UseSeed = 42, true }},
+		{"zero Seed", func(sch *Schedule) { sch.UseSeed = true }},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			want := NewSchedule()
+			test.edit(want)
+			data, err := json.Marshal(want)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := LoadSchedule(bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("LoadSchedule(%s): %v", data, err)
+			}
+			if again, err := json.Marshal(got); err != nil || !bytes.Equal(again, data) {
+				t.Errorf("Schedule encoded as %s, but as %s after loading", data, again)
+			}
+			switch {
+			case got.Iter != want.Iter || got.Ti != want.Ti || got.Tf != want.Tf || got.Duration != want.Duration:
+				t.Errorf("loaded Iter, Ti, Tf, Duration = %d, %v, %v, %v, want %d, %v, %v, %v",
+					got.Iter, got.Ti, got.Tf, got.Duration, want.Iter, want.Ti, want.Tf, want.Duration)
+			case got.Cooling != want.Cooling || got.CoolRate != want.CoolRate || got.NonFinite != want.NonFinite:
+				t.Errorf("loaded Cooling, CoolRate, NonFinite = %v, %v, %v, want %v, %v, %v",
+					got.Cooling, got.CoolRate, got.NonFinite, want.Cooling, want.CoolRate, want.NonFinite)
+			case got.UseTarget != want.UseTarget || got.Target != want.Target:
+				t.Errorf("loaded UseTarget, Target = %t, %v, want %t, %v", got.UseTarget, got.Target, want.UseTarget, want.Target)
+			case got.UseSeed != want.UseSeed || got.Seed != want.Seed:
+				t.Errorf("loaded UseSeed, Seed = %t, %v, want %t, %v", got.UseSeed, got.Seed, want.UseSeed, want.Seed)
+			case !maps.Equal(got.Labels, want.Labels):
+				t.Errorf("loaded Labels = %v, want %v", got.Labels, want.Labels)
+			}
+
+			// A Schedule value encodes as its pointer does.
+			if value, err := json.Marshal(*want); err != nil || !bytes.Equal(value, data) {
+				t.Errorf("Schedule value encoded as %s, want %s", value, data)
+			}
+		})
+	}
+}
+
+func TestLoadSchedule(t *testing.T) {
+	sch, err := LoadSchedule(strings.NewReader(`{"iter": 500, "duration": "1m30s", "non_finite": "abort", "cooling": "lundy-mees"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	def := NewSchedule()
+	if sch.Iter != 500 || sch.Duration != 90*time.Second || sch.NonFinite != AbortNonFinite || sch.Cooling != CoolLundyMees {
+		t.Errorf("loaded Iter, Duration, NonFinite, Cooling = %d, %v, %v, %v", sch.Iter, sch.Duration, sch.NonFinite, sch.Cooling)
+	}
+	if sch.Ti != def.Ti || sch.Tf != def.Tf || sch.Every != def.Every || sch.UseTarget || sch.UseSeed {
+		t.Errorf("omitted fields changed from their defaults: Ti, Tf, Every, UseTarget, UseSeed = %v, %v, %d, %t, %t",
+			sch.Ti, sch.Tf, sch.Every, sch.UseTarget, sch.UseSeed)
+	}
+
+	for _, test := range []struct {
+		name, config, err string
+	}{
+		{"unknown field", `{"iter": 500, "iters": 600}`, `unknown field "iters"`},
+		{"misspelled field", `{"coolRate": 0.9}`, `unknown field "coolRate"`},
+		{"invalid Duration", `{"duration": "soon"}`, "invalid duration"},
+		{"unknown NonFinite", `{"non_finite": "ignore"}`, "unknown NonFinite policy"},
+		{"wrong type", `{"iter": "many"}`, "cannot unmarshal"},
+		{"not an object", `[1, 2]`, "cannot unmarshal"},
+		{"negative Iter", `{"iter": -5}`, "Iter -5 is not positive"},
+		{"inverted temperatures", `{"ti": 0.1, "tf": 1}`, "Ti 0.1 does not exceed Tf 1"},
+		{"negative Duration", `{"duration": "-1s"}`, "Duration -1s is negative"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := LoadSchedule(strings.NewReader(test.config))
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("LoadSchedule(%s) error = %v, want one containing %q", test.config, err, test.err)
+			}
+		})
+	}
+}
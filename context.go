@@ -0,0 +1,58 @@
+package anneal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// ctxCheck is the interval in steps at which RunContext checks whether its Context is done.
+const ctxCheck = 64
+
+// RunContext anneals s as Run does, but stops early if ctx is done.
+// The Result then describes the search until it stopped, and the error wraps ctx.Err().
+// Cancellation takes effect between steps of the search, as described for Annealer.Step,
+// so a systematic scan in progress completes first.
+func RunContext(ctx context.Context, s State, opts ...Option) (Result, error) {
+	an, err := New(s, opts...)
+	if err != nil {
+		return Result{}, err
+	}
+	defer an.Close()
+	for i := 0; an.Step(); i++ {
+		if i%ctxCheck == 0 && ctx.Err() != nil {
+			err := fmt.Errorf("anneal: %w", context.Cause(ctx))
+			if ferr := an.a.flush(); ferr != nil {
+				err = errors.Join(err, ferr)
+			}
+			an.err = err // recorded in the span when Close ends it
+			return an.Result(), err
+		}
+	}
+	return an.Result(), an.Err()
+}
+
+// ErrInterrupted is returned by RunInterruptible when a signal stops the search.
+var ErrInterrupted = errors.New("anneal: interrupted")
+
+// RunInterruptible anneals s as Run does, but stops early when the process receives SIGINT or SIGTERM,
+// returning the Result of the search until then with ErrInterrupted,
+// so that an interrupted command-line experiment does not lose its partial results.
+// Once the first signal has been received, the default behavior is restored,
+// so that a second signal terminates the process.
+func RunInterruptible(s State, opts ...Option) (Result, error) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	r, err := RunContext(ctx, s, opts...)
+	if ctx.Err() != nil && errors.Is(err, context.Canceled) {
+		err = ErrInterrupted
+	}
+	return r, err
+}
@@ -0,0 +1,81 @@
+package anneal
+
+import (
+	"fmt"
+	"math"
+	"slices"
+)
+
+// A Convergence summarizes the final energies of independent runs of a search,
+// so that the caller can judge whether to trust the best of them or to run longer.
+// A best energy that many runs reach is plausibly the optimum, while one that a single run reaches
+// suggests that the runs are too short to converge, and that longer ones might do better.
+type Convergence struct {
+	Runs         int     // number of Results assessed
+	Mean, StdDev float64 // mean and sample standard deviation of the final energies
+	Median       float64 // median of the final energies
+	Best, Worst  float64 // lowest and highest final energies
+	BestState    State   // State of energy Best
+
+	Hits    int     // number of runs whose final energy is within the tolerance of Best
+	HitRate float64 // Hits / Runs, which estimates the probability that a run reaches Best
+
+	// Runs99 is the number of runs needed to reach Best with probability 0.99 at HitRate.
+	Runs99 int
+
+	// Risk bounds the probability that a run finds a State better than Best by more than the tolerance:
+	// since none of the runs did, the probability is less than Risk with 95% confidence.
+	// It depends only on Runs, and falls roughly as 3/Runs.
+	Risk float64
+}
+
+// Assess summarizes the final energies of results, which should come from independent runs of the same search,
+// treating energies within tol of the best as reaching it. It returns a Convergence with Runs zero if results is empty.
+func Assess(results []Result, tol float64) Convergence {
+	c := Convergence{Runs: len(results)}
+	if c.Runs == 0 {
+		return c
+	}
+	energies := make([]float64, len(results))
+	var sum, sumsq float64
+	c.Best, c.Worst = math.Inf(1), math.Inf(-1)
+	for i, r := range results {
+		e := r.Energy
+		energies[i] = e
+		sum += e
+		sumsq += e * e
+		if e < c.Best || c.BestState == nil {
+			c.Best, c.BestState = e, r.Best
+		}
+		c.Worst = max(c.Worst, e)
+	}
+	n := float64(c.Runs)
+	c.Mean = sum / n
+	if c.Runs > 1 {
+		c.StdDev = math.Sqrt(max(0, (sumsq-sum*sum/n)/(n-1)))
+	}
+	slices.Sort(energies)
+	if m := c.Runs / 2; c.Runs%2 == 1 {
+		c.Median = energies[m]
+	} else {
+		c.Median = (energies[m-1] + energies[m]) / 2
+	}
+	for _, e := range energies {
+		if e-c.Best <= tol {
+			c.Hits++
+		}
+	}
+	c.HitRate = float64(c.Hits) / n
+	if c.HitRate == 1 {
+		c.Runs99 = 1
+	} else {
+		c.Runs99 = int(math.Ceil(math.Log(0.01) / math.Log1p(-c.HitRate)))
+	}
+	c.Risk = -math.Expm1(math.Log(0.05) / n)
+	return c
+}
+
+func (c Convergence) String() string {
+	return fmt.Sprintf("%d runs, best %v reached by %d (%.0f%%), %d runs for 99%% probability, mean %v ± %v, median %v, worst %v, risk of a better State %.2g",
+		c.Runs, c.Best, c.Hits, 100*c.HitRate, c.Runs99, c.Mean, c.StdDev, c.Median, c.Worst, c.Risk)
+}
@@ -0,0 +1,77 @@
+package anneal
+
+import (
+	"fmt"
+	"math"
+)
+
+// A Cooling is a law by which the temperature decays during each run from Ti to Tf.
+// Each law is a function of the iteration index i, or of the time elapsed in the run if Schedule.Duration is positive,
+// and of a rate, Schedule.CoolRate, which by default is chosen so that the temperature reaches Tf at the end of the run.
+// If Schedule.Block is greater than 1, i is instead the index of the temperature level, and each step of a law
+// below is taken once per level rather than once per iteration.
+type Cooling int
+
+const (
+	// CoolExponential decays the temperature exponentially, as described for Anneal: T = Ti * exp(-i/k).
+	CoolExponential Cooling = iota
+
+	// CoolGeometric multiplies the temperature by the factor CoolRate, which must be in (0, 1), at each iteration:
+	// T = Ti * CoolRate^i. The temperature then reaches Tf at the end of the run only if CoolRate = (Tf/Ti)^(1/Iter),
+	// which is the rate that a CoolRate of zero selects, making the law the same as CoolExponential.
+	CoolGeometric
+
+	// CoolLundyMees decays the temperature by the rule of Lundy and Mees (1986), T ← T / (1 + β T) at each iteration,
+	// where β is CoolRate in units of the inverse of Ti: T = Ti / (1 + β i Ti). The temperature falls quickly at first
+	// and then ever more slowly, so that the search spends much of the run near its final temperatures,
+	// which suits landscapes with long plateaus. A CoolRate of zero selects β = (1/Tf - 1/Ti) / Iter,
+	// or the same with Duration in nanoseconds in place of Iter.
+	CoolLundyMees
+)
+
+var coolingNames = [...]string{
+	CoolExponential: "exponential",
+	CoolGeometric:   "geometric",
+	CoolLundyMees:   "lundy-mees",
+}
+
+func (c Cooling) String() string {
+	if c >= 0 && int(c) < len(coolingNames) {
+		return coolingNames[c]
+	}
+	return fmt.Sprintf("Cooling(%d)", int(c))
+}
+
+// MarshalText encodes c as one of "exponential", "geometric", or "lundy-mees".
+func (c Cooling) MarshalText() ([]byte, error) {
+	if c < 0 || int(c) >= len(coolingNames) {
+		return nil, fmt.Errorf("anneal: unknown Cooling %d", int(c))
+	}
+	return []byte(coolingNames[c]), nil
+}
+
+// UnmarshalText decodes a law encoded by MarshalText.
+func (c *Cooling) UnmarshalText(text []byte) error {
+	for i, name := range coolingNames {
+		if string(text) == name {
+			*c = Cooling(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("anneal: unknown Cooling %q", text)
+}
+
+// coolingScale returns the scale k of the law of sch, in units of x, where x is the index of the temperature level,
+// or the time elapsed in nanoseconds if the run is timed, and n is the length of the run in the same units.
+// The exponential and geometric laws are T0 * exp(-x/k), and the Lundy–Mees law is T0 / (1 + x/k).
+func coolingScale(sch *Schedule, n float64) float64 {
+	switch {
+	case sch.Cooling == CoolGeometric && sch.CoolRate > 0:
+		return -1 / math.Log(sch.CoolRate)
+	case sch.Cooling == CoolLundyMees && sch.CoolRate > 0:
+		return 1 / (sch.CoolRate * sch.Ti)
+	case sch.Cooling == CoolLundyMees:
+		return n / (sch.Ti/sch.Tf - 1)
+	}
+	return n / math.Log(sch.Ti/sch.Tf)
+}
@@ -0,0 +1,80 @@
+/*
+Package core implements the essential simulated annealing loop with no dependencies beyond package math.
+
+It uses no reflection, goroutines, or global random state, and the loop allocates nothing itself,
+so it is suitable for TinyGo and other embedded targets, for example to tune control parameters on a device.
+States are type parameters rather than interfaces, so Neighbor may return a value type
+and an iteration need not allocate at all.
+
+The annealing process is the same as that of package anneal without its optional features:
+see anneal.Anneal for the acceptance rule and the meaning of the Schedule parameters.
+*/
+package core
+
+import "math"
+
+// A State can undergo simulated annealing optimization.
+// S is the type of its neighbors, normally the implementing type itself.
+type State[S any] interface {
+	// Energy returns the energy of the State. Smaller energies are better.
+	Energy() float64
+
+	// Neighbor returns a State chosen randomly from those adjacent to the current State.
+	Neighbor() S
+}
+
+// A Schedule controls the annealing process.
+type Schedule struct {
+	Iter int     // number of iterations
+	Ti   float64 // initial temperature, as a multiple of the input State's energy
+	Tf   float64 // final temperature, as a multiple of the input State's energy
+}
+
+// A Rand is a source of uniformly distributed random numbers in [0, 1).
+type Rand interface {
+	Float64() float64
+}
+
+// Anneal anneals s according to sch, drawing acceptance decisions from r,
+// and returns the best State encountered.
+func Anneal[S State[S]](s S, sch Schedule, r Rand) S {
+	e := s.Energy()
+	best, ebest := s, e
+	T0, k := math.Abs(e)*sch.Ti, Scale(float64(sch.Iter), sch.Ti, sch.Tf)
+	for i := 0; i < sch.Iter; i++ {
+		next := s.Neighbor()
+		enew := next.Energy()
+		if enew < ebest {
+			best, ebest = next, enew
+		}
+		if enew < e || Accept(enew-e, T0*math.Exp(-float64(i)/k), r.Float64()) {
+			s, e = next, enew
+		}
+	}
+	return best
+}
+
+// Scale returns the number of steps required for the temperature to drop by a factor of e
+// when it decays exponentially from ti to tf over n steps.
+func Scale(n, ti, tf float64) float64 {
+	return n / math.Log(ti/tf)
+}
+
+// Accept reports whether to adopt a State whose energy exceeds the current energy by dE at temperature T,
+// given a uniform random number u in [0, 1).
+func Accept(dE, T, u float64) bool {
+	return u <= math.Exp(-dE/T)
+}
+
+// An XorShift is a small, fast pseudo-random number generator implementing Rand.
+// Its value is its internal state, which must be nonzero.
+type XorShift uint64
+
+// Float64 returns a pseudo-random number in [0, 1).
+func (x *XorShift) Float64() float64 {
+	// xorshift64* (Vigna, 2016)
+	*x ^= *x >> 12
+	*x ^= *x << 25
+	*x ^= *x >> 27
+	return float64((uint64(*x)*0x2545F4914F6CDD1D)>>11) / (1 << 53)
+}
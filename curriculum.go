@@ -0,0 +1,71 @@
+package anneal
+
+import (
+	"fmt"
+	"slices"
+)
+
+// A Stage is one problem instance of a Curriculum.
+type Stage struct {
+	Size int // size of the instance, by which a Curriculum may order its Stages
+
+	// Start returns the starting State for the instance, given the best State of the previous Stage,
+	// or nil for the first Stage. It might embed the previous solution in the larger instance.
+	Start func(prev State) (State, error)
+}
+
+// A Curriculum anneals a sequence of progressively harder problem instances,
+// each starting from the solution of the one before, so as to reach instances
+// that are hopeless to anneal from a cold start.
+type Curriculum struct {
+	Stages []Stage
+	BySize bool // whether to anneal the Stages in order of increasing Size rather than in the given order
+
+	// ScaleIter, if true, scales Schedule.Iter in each Stage in proportion to its Size relative to the first Stage.
+	ScaleIter bool
+
+	// Reheat, if positive, sets the initial temperature of each Stage after the first to Reheat times
+	// the BestTemperature of the previous Stage, but no higher than Schedule.Ti,
+	// so that a transferred solution is refined rather than melted.
+	// Stages whose computed temperature would not exceed Schedule.Tf use Schedule.Ti.
+	Reheat float64
+}
+
+// Run anneals the Stages of c in turn with the Schedule configured by opts and returns a Result for each Stage begun.
+// It stops at the first error, which it annotates with the index of the Stage in the order annealed.
+func (c *Curriculum) Run(opts ...Option) ([]Result, error) {
+	sch := configure(opts)
+	if err := sch.Validate(); err != nil {
+		return nil, err
+	}
+	stages := slices.Clone(c.Stages)
+	if c.BySize {
+		slices.SortStableFunc(stages, func(a, b Stage) int { return a.Size - b.Size })
+	}
+	var (
+		results []Result
+		prev    State
+	)
+	for k, st := range stages {
+		s, err := st.Start(prev)
+		if err != nil {
+			return results, fmt.Errorf("anneal: curriculum stage %d: %w", k, err)
+		}
+		stage := *sch
+		if c.ScaleIter && k > 0 && stages[0].Size > 0 {
+			stage.Iter = max(1, int(float64(sch.Iter)*float64(st.Size)/float64(stages[0].Size)))
+		}
+		if c.Reheat > 0 && k > 0 {
+			if ti := min(sch.Ti, c.Reheat*results[k-1].BestTemperature); ti > sch.Tf {
+				stage.Ti = ti
+			}
+		}
+		r, err := Run(s, &stage)
+		results = append(results, r)
+		if err != nil {
+			return results, fmt.Errorf("anneal: curriculum stage %d: %w", k, err)
+		}
+		prev = r.Best
+	}
+	return results, nil
+}
@@ -0,0 +1,156 @@
+package anneal
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// A Partitioner divides a large State into blocks of variables that can be annealed separately,
+// for States too large to anneal as a whole with neighbors that copy the entire State.
+// Blocks must be disjoint, so that replacing one does not affect another.
+type Partitioner interface {
+	// Blocks returns the number of blocks of s.
+	Blocks(s State) int
+
+	// Block returns a State representing block i of s, whose neighbors alter only that block
+	// while the rest of s stays fixed. Its energy should differ from that of s by a quantity
+	// that does not depend on the block, so that improving the block improves s.
+	Block(s State, i int) State
+
+	// Assemble returns s with block i replaced by b, a State derived from Block(t, i)
+	// for some t that differs from s at most in other blocks. It must not modify s.
+	Assemble(s State, i int, b State) State
+}
+
+// A Decomposition anneals a State block by block.
+// In each round, it anneals every block of the current State with the rest held fixed and reassembles the results.
+type Decomposition struct {
+	Partitioner Partitioner
+	Rounds      int // number of rounds; values less than 1 mean 1
+
+	// Parallel, if true, anneals the blocks of each round concurrently, starting from the same State.
+	// A coordination step then installs all of the annealed blocks at once if that does not increase
+	// the energy of the whole, and otherwise installs them one at a time, keeping each that does not.
+	// Because the blocks share the Schedule, its Observer, Policy, and Acceptor must be safe for concurrent use,
+	// and its Rand is not used. Otherwise, the blocks are annealed in rotation, each starting from the State
+	// that includes the blocks annealed before it.
+	Parallel bool
+}
+
+// Run anneals s by blocks with the Schedule configured by opts.
+// The returned Result describes the whole State: Best is the final assembled State, Energy its energy,
+// Evaluations counts the evaluations of all blocks and of the assembled States, and RunUsage lists the block runs.
+func (d *Decomposition) Run(s State, opts ...Option) (Result, error) {
+	sch := configure(opts)
+	if err := sch.Validate(); err != nil {
+		return Result{}, err
+	}
+	e, err := energy(s)
+	if err != nil {
+		return Result{}, fmt.Errorf("anneal: input State: %w", err)
+	}
+	usage := sampleUsage()
+	res := Result{Best: s, Energy: e, Evaluations: 1}
+	var peak uint64
+	record := func(r Result) {
+		res.Evaluations += r.Evaluations
+		res.RunUsage = append(res.RunUsage, r.RunUsage...)
+		peak = max(peak, r.Usage.PeakMemory)
+	}
+	for round := 0; round < max(d.Rounds, 1); round++ {
+		if d.Parallel {
+			err = d.parallel(&res, sch, record)
+		} else {
+			err = d.rotate(&res, sch, record)
+		}
+		if err != nil {
+			err = fmt.Errorf("anneal: decomposition round %d: %w", round, err)
+			break
+		}
+	}
+	res.Usage = sampleUsage().since(usage, peak)
+	return res, err
+}
+
+// rotate anneals the blocks of res.Best in turn, installing each in res.Best.
+func (d *Decomposition) rotate(res *Result, sch *Schedule, record func(Result)) error {
+	p := d.Partitioner
+	for i := 0; i < p.Blocks(res.Best); i++ {
+		r, err := Run(p.Block(res.Best, i), sch)
+		record(r)
+		if err != nil {
+			return fmt.Errorf("block %d: %w", i, err)
+		}
+		if err := d.install(res, i, r.Best); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parallel anneals the blocks of res.Best concurrently and coordinates their installation.
+func (d *Decomposition) parallel(res *Result, sch *Schedule, record func(Result)) error {
+	p := d.Partitioner
+	n := p.Blocks(res.Best)
+	blocks := make([]State, n)
+	for i := range blocks {
+		blocks[i] = p.Block(res.Best, i)
+	}
+	bs := *sch
+	bs.Rand = nil
+	results := make([]Result, n)
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, runtime.GOMAXPROCS(0))
+	for i, b := range blocks {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() { <-sem; wg.Done() }()
+			results[i], errs[i] = Run(b, &bs)
+		}()
+	}
+	wg.Wait()
+	for i, r := range results {
+		record(r)
+		if errs[i] != nil {
+			return fmt.Errorf("block %d: %w", i, errs[i])
+		}
+	}
+
+	// Coordinate: install every block at once if the whole does not get worse.
+	merged := res.Best
+	for i, r := range results {
+		merged = p.Assemble(merged, i, r.Best)
+	}
+	e, err := energy(merged)
+	res.Evaluations++
+	if err != nil {
+		return fmt.Errorf("assembled State: %w", err)
+	}
+	if e <= res.Energy {
+		res.Best, res.Energy = merged, e
+		return nil
+	}
+	for i, r := range results {
+		if err := d.install(res, i, r.Best); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// install replaces block i of res.Best with b if doing so does not increase the energy of the whole.
+func (d *Decomposition) install(res *Result, i int, b State) error {
+	s := d.Partitioner.Assemble(res.Best, i, b)
+	e, err := energy(s)
+	res.Evaluations++
+	if err != nil {
+		return fmt.Errorf("block %d: assembled State: %w", i, err)
+	}
+	if e <= res.Energy {
+		res.Best, res.Energy = s, e
+	}
+	return nil
+}
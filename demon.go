@@ -0,0 +1,34 @@
+package anneal
+
+// A Demon is an Acceptor that implements microcanonical annealing by the demon algorithm.
+// The demon holds a budget of energy: it adopts a proposal if and only if the increase in energy
+// does not exceed its budget, which then pays for the increase or absorbs the decrease.
+// Annealing comes from bounding the budget by Scale times the temperature, so that the demon
+// sheds energy as the system cools. Decisions are deterministic and need no random numbers
+// or exponentials, which makes each iteration cheaper and runs reproducible given the States' proposals.
+//
+// The budget starts full at the beginning of each run. A Demon keeps state between decisions,
+// so it must not be shared by concurrent searches.
+type Demon struct {
+	Energy float64 // current budget of the demon
+	Scale  float64 // bound on Energy as a multiple of the temperature; values not greater than 0 mean 1
+
+	run     int
+	started bool
+}
+
+// Accept adopts d.Proposed if d.Delta does not exceed the demon's budget, and transfers the difference.
+func (dm *Demon) Accept(d Decision) (bool, error) {
+	limit := d.Temperature
+	if dm.Scale > 0 {
+		limit *= dm.Scale
+	}
+	if !dm.started || d.Run != dm.run {
+		dm.Energy, dm.run, dm.started = limit, d.Run, true
+	}
+	if !(d.Delta <= dm.Energy) {
+		return false, nil
+	}
+	dm.Energy = min(dm.Energy-d.Delta, limit)
+	return true, nil
+}
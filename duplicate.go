@@ -0,0 +1,80 @@
+package anneal
+
+// A Hasher is a State that can summarize itself in a hash,
+// so that Anneal can detect proposals that duplicate recently seen States; see Schedule.Recent.
+type Hasher interface {
+	State
+
+	// Hash returns a hash of the State. Equal States must have equal hashes,
+	// and distinct States should have distinct hashes with high probability.
+	Hash() uint64
+}
+
+// DuplicateStats describes how often proposed neighbors duplicated recently seen States.
+// A high rate suggests that the neighborhood is too small or the temperature too low
+// for the search to make progress.
+type DuplicateStats struct {
+	Proposals  int // number of proposals checked
+	Duplicates int // number of proposals whose hash matched a recently seen State
+	Streak     int // longest run of consecutive duplicate proposals
+}
+
+// Rate returns the fraction of proposals that were duplicates.
+func (d *DuplicateStats) Rate() float64 {
+	if d.Proposals == 0 {
+		return 0
+	}
+	return float64(d.Duplicates) / float64(d.Proposals)
+}
+
+// A recent remembers the hashes of a fixed number of recently seen States.
+type recent struct {
+	ring   []uint64
+	next   int // index in ring of the next hash to overwrite
+	n      int // number of hashes in ring
+	count  map[uint64]int
+	streak int // length of the current run of duplicates
+	stats  DuplicateStats
+}
+
+func newRecent(size int) *recent {
+	return &recent{ring: make([]uint64, size), count: make(map[uint64]int)}
+}
+
+// see records s as seen and reports whether it duplicated a recent State.
+// States that do not implement Hasher are ignored.
+func (r *recent) see(s State) bool {
+	h, ok := s.(Hasher)
+	if !ok {
+		return false
+	}
+	x := h.Hash()
+	dup := r.count[x] > 0
+	if r.n == len(r.ring) {
+		old := r.ring[r.next]
+		if r.count[old]--; r.count[old] == 0 {
+			delete(r.count, old)
+		}
+	} else {
+		r.n++
+	}
+	r.ring[r.next] = x
+	r.next = (r.next + 1) % len(r.ring)
+	r.count[x]++
+	return dup
+}
+
+// propose records a proposal and updates the statistics.
+func (r *recent) propose(s State) {
+	if _, ok := s.(Hasher); !ok {
+		return
+	}
+	r.stats.Proposals++
+	if r.see(s) {
+		r.stats.Duplicates++
+		r.streak++
+		r.stats.Streak = max(r.stats.Streak, r.streak)
+	} else {
+		r.streak = 0
+	}
+}
@@ -0,0 +1,77 @@
+package anneal
+
+import "sort"
+
+// An Elite is one of the best distinct States encountered during annealing, with its energy.
+type Elite struct {
+	State  State
+	Energy float64
+}
+
+// An archive retains the k lowest-energy distinct States offered to it.
+type archive struct {
+	k      int
+	equal  func(a, b State) bool // equality of States that do not implement Hasher, or nil
+	elite  []Elite               // sorted by increasing energy
+	hashes map[uint64]int        // hashes of archived Hasher States, with multiplicity
+}
+
+func newArchive(k int, equal func(a, b State) bool) *archive {
+	return &archive{k: k, equal: equal, hashes: make(map[uint64]int)}
+}
+
+// offer considers s, whose energy is e, for inclusion in the archive.
+func (ar *archive) offer(s State, e float64) {
+	if len(ar.elite) == ar.k && e >= ar.elite[len(ar.elite)-1].Energy {
+		return
+	}
+	if ar.contains(s) {
+		return
+	}
+	if len(ar.elite) == ar.k {
+		ar.forget(ar.elite[len(ar.elite)-1].State)
+		ar.elite = ar.elite[:len(ar.elite)-1]
+	}
+	i := sort.Search(len(ar.elite), func(i int) bool { return ar.elite[i].Energy > e })
+	ar.elite = append(ar.elite, Elite{})
+	copy(ar.elite[i+1:], ar.elite[i:])
+	ar.elite[i] = Elite{s, e}
+	if h, ok := s.(Hasher); ok {
+		ar.hashes[h.Hash()]++
+	}
+}
+
+// contains reports whether the archive holds a State equal to s.
+// States are compared by Hash if they implement Hasher, and otherwise by the equality function;
+// with neither, no two States are considered equal.
+func (ar *archive) contains(s State) bool {
+	if h, ok := s.(Hasher); ok {
+		return ar.hashes[h.Hash()] > 0
+	}
+	if ar.equal == nil {
+		return false
+	}
+	for _, el := range ar.elite {
+		if ar.equal(s, el.State) {
+			return true
+		}
+	}
+	return false
+}
+
+// forget removes the hash of s, which is being evicted.
+func (ar *archive) forget(s State) {
+	h, ok := s.(Hasher)
+	if !ok {
+		return
+	}
+	x := h.Hash()
+	if ar.hashes[x]--; ar.hashes[x] <= 0 {
+		delete(ar.hashes, x)
+	}
+}
+
+// list returns a copy of the archived States in order of increasing energy.
+func (ar *archive) list() []Elite {
+	return append([]Elite(nil), ar.elite...)
+}
@@ -0,0 +1,587 @@
+package anneal
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/dkmccandless/anneal/core"
+)
+
+// An annealer holds the state of an annealing process that persists across restarts.
+type annealer struct {
+	iter     int
+	duration time.Duration // length of each run, or 0 if runs are measured in iterations
+	scale    float64       // scale of temperatures; see energyScale
+	T0, k    float64       // k is in iterations, or in nanoseconds if duration is positive; see coolingScale
+	tend     float64       // temperature at the end of the most recent run
+	cooling  Cooling       // law of the decay of the temperature
+	block    int           // number of iterations at each temperature level
+	start    time.Time     // start of the current run
+	clock    Clock         // source of the current time
+	mem      *memory       // long-term frequency memory, or nil if diversification is not in use
+	workers  *pool         // pool evaluating neighbors concurrently, or nil
+	batch    []proposal
+	scanT    float64 // temperature below which to scan systematically
+	audit    int     // energy audit interval, or 0
+	scaleTo  float64 // target acceptance rate of the scaling controller, or 0; see Scaler
+	scaleWin int     // number of iterations between adjustments of the scaling factor
+	runs     int     // number of runs begun
+	obs      Observer
+	every    int     // current interval between progress reports
+	minEvery int     // configured interval between progress reports
+	overhead float64 // maximum fraction of time to spend reporting, or 0
+	obsTime  time.Duration
+	policy   Policy        // chooser of operators for Movers, or nil until one is needed
+	moves    []MoveStats   // statistics of the operators of Movers, or nil until one is needed
+	recent   *recent       // recently seen States, or nil if duplicates are not tracked
+	archive  *archive      // best distinct States, or nil if they are not retained
+	evals    int           // number of energy evaluations performed, including that of the input State
+	maxEvals int           // limit on evals, or 0
+	nsample  [2]int        // numbers of energy evaluations averaged per proposal at the start and end of each run
+	logRatio float64       // log(Ti/Tf)
+	target   *float64      // energy at which to stop, or nil
+	land     *landLog      // landscape log, or nil
+	record   *decisionLog  // decision log being written, or nil
+	replay   *replayer     // decision log being replayed, or nil
+	inits    *initializers // Initializers of restarts, or nil to restart from the best State
+	srand    *rand.Rand    // source of randomness for Seeded States, or nil
+	shared   *Shared       // best State shared with cooperating searches, or nil
+	hist     *history      // most recently adopted States, or nil if they are not recorded
+	local    LocalSearch   // problem-specific improvement procedure, or nil
+	localK   int           // interval in iterations between local searches, or 0 to search from each new best State
+	localN   int           // budget of each local search
+	rate     float64       // limit on evaluations per second, or 0
+	pace0    time.Time     // system time at which pacing began
+	nextPace int           // value of evals at which to compare progress with the clock
+	yield    int           // interval in evaluations between yields of the processor, or 0
+	nextYld  int           // value of evals at which to yield
+	xchg     int           // interval in iterations between exchanges with shared, or 0
+
+	rand          func() float64
+	better        func(s State, e float64, best State, ebest float64) bool // comparison of States for the best, or nil to compare energies
+	prob          func(dE, T float64) float64                              // acceptance probability, or nil for the Metropolis criterion
+	plateau       float64                                                  // tolerance of energy differences treated as zero
+	plateauAccept float64                                                  // probability of adopting a proposal within the tolerance, or 0 for 1
+	acceptor      Acceptor                                                 // acceptance oracle, or nil
+	nonFinite     NonFinite
+	labels        map[string]string // labels identifying the search
+
+	cur   State // current State of the most recent run
+	best  State
+	ebest float64
+	tbest float64      // temperature relative to scale at which best was found during a run, or 0
+	wins  int          // number of times the best State has been replaced
+	cert  *Certificate // certificate of local optimality of best, or nil
+	drift *Drift       // first energy discrepancy detected by the audit, or nil
+	usage []Usage      // resources consumed by each run
+	peak  uint64       // peak memory observed
+}
+
+// newAnnealer returns an annealer for the input State s, whose energy is e.
+// The caller must call close when finished with it.
+func newAnnealer(s State, e float64, sch *Schedule) *annealer {
+	a := &annealer{
+		iter:          sch.Iter,
+		scale:         energyScale(e),
+		T0:            energyScale(e) * sch.Ti,
+		k:             coolingScale(sch, float64(sch.Iter)/float64(max(sch.Block, 1))),
+		cooling:       sch.Cooling,
+		block:         max(sch.Block, 1),
+		batch:         make([]proposal, max(sch.Workers, 1)),
+		scanT:         math.Inf(-1),
+		audit:         sch.Audit,
+		scaleTo:       sch.AcceptTarget,
+		scaleWin:      cmp.Or(sch.ScaleWindow, defaultScaleWindow),
+		obs:           sch.Observer,
+		every:         max(sch.Every, 1),
+		overhead:      sch.MaxOverhead,
+		rand:          rand.Float64,
+		prob:          sch.AcceptProb,
+		better:        sch.Better,
+		acceptor:      sch.Acceptor,
+		plateau:       sch.Plateau,
+		plateauAccept: sch.PlateauAccept,
+		nonFinite:     sch.NonFinite,
+		policy:        sch.Policy,
+		evals:         max(sch.Samples, 1),
+		maxEvals:      sch.Evals,
+		nsample:       [2]int{max(sch.Samples, 1), max(sch.Samples, sch.MaxSamples, 1)},
+		logRatio:      math.Log(sch.Ti / sch.Tf),
+		best:          keep(s),
+		ebest:         e,
+	}
+	a.minEvery = a.every
+	a.labels = maps.Clone(sch.Labels)
+	a.clock = sch.Clock
+	if a.clock == nil {
+		a.clock = systemClock{}
+	}
+	if _, ok := s.(Snapshotter); ok && sch.History > 0 {
+		a.hist = newHistory(sch.History)
+	}
+	if _, ok := s.(Hasher); ok && sch.Landscape != nil {
+		a.land = newLandLog(sch.Landscape)
+	}
+	if sch.Decisions != nil {
+		a.record = newDecisionLog(sch.Decisions)
+	}
+	if sch.UseTarget {
+		a.target = &sch.Target
+	}
+	if sch.Rand != nil {
+		a.rand = sch.Rand.Float64
+	}
+	if sch.UseSeed {
+		a.rand = newSeededRand(sch.Seed, decisionStream).Float64
+		a.srand = newSeededRand(sch.Seed, stateStream)
+		a.provide(s)
+	}
+	if sch.Duration > 0 {
+		a.duration = sch.Duration
+		a.k = coolingScale(sch, float64(sch.Duration))
+	}
+	if _, ok := s.(Enumerator); ok && sch.Scan > 0 {
+		a.scanT = energyScale(e) * sch.Scan
+	}
+	if sch.Workers > 1 {
+		a.workers = newPool(sch.Workers)
+	}
+	if len(sch.Initializers) > 0 {
+		a.inits = newInitializers(sch.Initializers)
+	}
+	a.rate, a.yield = sch.Rate, sch.Yield
+	if sch.LocalSearch != nil {
+		a.local, a.localK, a.localN = sch.LocalSearch, sch.LocalEvery, cmp.Or(sch.LocalBudget, defaultLocalBudget)
+	}
+	if sch.Shared != nil {
+		a.shared, a.xchg = sch.Shared, sch.Exchange
+		a.shared.offer(a.best, e)
+	}
+	if sch.Keep > 0 {
+		a.archive = newArchive(sch.Keep, sch.Equal)
+		a.archive.offer(s, e)
+	}
+	if _, ok := s.(Hasher); ok && sch.Recent > 0 {
+		a.recent = newRecent(sch.Recent)
+		a.recent.see(s)
+	}
+	if _, ok := s.(Componenter); ok && sch.Restarts > 0 && sch.Diversify != 0 {
+		a.mem = newMemory(sch.Diversify)
+		a.mem.record(s)
+	}
+	return a
+}
+
+// close releases the resources held by a.
+func (a *annealer) close() {
+	if a.workers != nil {
+		a.workers.close()
+		a.workers = nil
+	}
+}
+
+// A proposal is a neighbor and its energy, or the error that prevented obtaining them.
+// A proposal whose State is nil has been rejected for its energy.
+type proposal struct {
+	s    State
+	e    float64
+	err  error
+	move int  // operator chosen by the Policy, or -1
+	best bool // whether the proposal became the best State
+}
+
+// A chain is the progress of one run.
+type chain struct {
+	s         State
+	e, f      float64 // energy of s, and energy plus the memory penalty if diversifying
+	diversify bool
+	i         int // number of iterations completed
+	accepted  int
+	nextAudit int
+	nextRpt   int
+	nextXchg  int
+	nextLocal int
+	init      int // index of the Initializer of s, or -1
+	wins      int // value of annealer.wins when the run began
+	usage     usageSample
+
+	factor    float64 // scaling factor of proposals; see Scaler
+	nextScale int
+	scaleAcc  int // value of accepted at the last adjustment of factor
+	scaleIter int // value of i at the last adjustment of factor
+
+	probeStart     time.Time // start of the run by the system clock, for the decision to sprint
+	probed, sprint bool
+}
+
+// run performs one pass of the schedule starting from s, whose energy is e.
+// If diversify is true, States are adopted according to their energies plus the memory penalty,
+// but the best State is still determined by energy alone.
+// It stops at the first error returned by a FallibleEnergy or FallibleNeighbor.
+func (a *annealer) run(s State, e float64, diversify bool) error {
+	c := a.begin(s, e, diversify)
+	defer a.end(c)
+	for {
+		more, err := a.step(c)
+		if err != nil || !more {
+			return err
+		}
+	}
+}
+
+// begin starts a run from s, whose energy is e, as described for run.
+// The caller must call end when the run is complete.
+func (a *annealer) begin(s State, e float64, diversify bool) *chain {
+	if a.cur != nil {
+		notify(a.cur, s)
+	}
+	c := &chain{s: s, e: e, f: e, diversify: diversify, init: -1, factor: 1, nextXchg: a.xchg, nextLocal: a.localK, wins: a.wins, usage: sampleUsage(), probeStart: time.Now()}
+	a.runs++
+	a.start = a.clock.Now()
+	a.obsTime = 0
+	if diversify {
+		c.f += a.mem.penalty(s)
+	}
+	return c
+}
+
+// end records the completion of the run c.
+func (a *annealer) end(c *chain) {
+	a.cur = c.s
+	a.tend = a.temp(c.i)
+	a.measure(c.usage)
+	if c.init >= 0 {
+		a.inits.stats[c.init].Runs++
+		if a.wins > c.wins {
+			a.inits.stats[c.init].Wins++
+		}
+	}
+}
+
+// step advances the run c by one iteration, or by one batch of iterations if a pool is in use,
+// or by many iterations if the run sprints, and reports whether the run continues.
+func (a *annealer) step(c *chain) (bool, error) {
+	if c.sprint {
+		return a.sprint(c)
+	}
+	if a.done(c.i) {
+		if a.obs != nil {
+			a.report(c.i, a.temp(c.i), c.e, c.accepted)
+		}
+		return false, nil
+	}
+	if a.audit > 0 && c.i >= c.nextAudit {
+		a.check(c.s, c.e, c.i)
+		c.nextAudit = c.i + a.audit
+	}
+	if a.xchg > 0 && c.i >= c.nextXchg {
+		a.exchange(c)
+		c.nextXchg = c.i + a.xchg
+	}
+	if a.localK > 0 && c.i >= c.nextLocal {
+		if err := a.improve(c); err != nil {
+			return false, a.errorf(c.i, err)
+		}
+		c.nextLocal = c.i + a.localK
+	}
+	T := a.temp(c.i)
+	if a.obs != nil && c.i >= c.nextRpt {
+		a.report(c.i, T, c.e, c.accepted)
+		c.nextRpt = c.i + a.every
+	}
+	if T < a.scanT {
+		from := keep(a.best)
+		notify(c.s, from)
+		s, e, err := a.scan(from, a.ebest)
+		// The run ends at the local optimum, which it adopts as though it had been proposed.
+		c.s, c.e, c.f = s, e, e
+		if c.diversify {
+			c.f += a.mem.penalty(s)
+		}
+		a.adopted(s, e, a.runs-1, c.i)
+		if a.mem != nil {
+			a.mem.record(s)
+		}
+		if err != nil {
+			return false, a.errorf(c.i, err)
+		}
+		if a.obs != nil {
+			a.report(c.i, T, a.ebest, c.accepted)
+		}
+		return false, nil
+	}
+	if a.scaleTo > 0 {
+		a.rescale(c)
+	}
+	a.pace()
+	batch := a.propose(c.s, c.e, a.remaining(c.i), T)
+	for j := range batch {
+		p := &batch[j]
+		if p.err != nil {
+			return false, a.errorf(c.i+j, p.err)
+		}
+		ok, err := a.admit(p.e)
+		if err != nil {
+			return false, a.errorf(c.i+j, err)
+		}
+		if !ok {
+			a.recycle(p.s, false)
+			p.s = nil
+			continue
+		}
+		if p.best = a.consider(p.s, p.e); p.best {
+			a.tbest = T / a.scale
+		}
+	}
+	parent, adopted := c.s, -1
+	for j, p := range batch {
+		if p.s == nil {
+			// The proposal was rejected for its energy.
+			a.reward(p, math.NaN(), false)
+			c.i++
+			continue
+		}
+		if a.recent != nil {
+			a.recent.propose(p.s)
+		}
+		fnew := p.e
+		if c.diversify {
+			fnew += a.mem.penalty(p.s)
+		}
+		ok, err := a.decide(c, p, fnew)
+		if err != nil {
+			return false, a.errorf(c.i, err)
+		}
+		a.reward(p, delta(p.e, c.e), ok)
+		c.i++
+		if ok {
+			notify(c.s, p.s)
+			c.s, c.e, c.f = p.s, p.e, fnew
+			c.accepted++
+			a.adopted(c.s, c.e, a.runs-1, c.i-1)
+			if a.mem != nil {
+				a.mem.record(c.s)
+			}
+			adopted = j
+			break
+		}
+	}
+	if a.land != nil {
+		for j, p := range batch {
+			var flags byte
+			if adopted < 0 || j <= adopted {
+				flags |= LandscapeConsidered
+			}
+			if j == adopted {
+				flags |= LandscapeAccepted
+			}
+			a.log(parent, p.s, p.e, flags)
+		}
+	}
+	for j, p := range batch {
+		if j != adopted && p.s != nil {
+			a.recycle(p.s, p.best)
+		}
+	}
+	if a.local != nil && a.localK == 0 && adopted >= 0 && batch[adopted].best {
+		if err := a.improve(c); err != nil {
+			return false, a.errorf(c.i, err)
+		}
+	}
+	if !c.probed && c.i >= probeLen {
+		a.probe(c)
+	}
+	return true, nil
+}
+
+// consider updates the best State and the archive with s, whose energy is e,
+// and reports whether s became the best State.
+func (a *annealer) consider(s State, e float64) bool {
+	best := a.promote(s, e)
+	if a.archive != nil {
+		a.archive.offer(s, e)
+	}
+	return best
+}
+
+// promote makes s, whose energy is e, the best State if it is better, and reports whether it did.
+func (a *annealer) promote(s State, e float64) bool {
+	better := less(s, e, a.best, a.ebest)
+	if a.better != nil {
+		better = a.better(s, e, a.best, a.ebest)
+	}
+	if !better {
+		return false
+	}
+	a.best, a.ebest, a.cert, a.tbest = keep(s), e, nil, 0
+	a.wins++
+	if a.shared != nil {
+		a.shared.offer(a.best, e)
+	}
+	return true
+}
+
+// elite returns the archived States, or nil if they are not retained.
+func (a *annealer) elite() []Elite {
+	if a.archive == nil {
+		return nil
+	}
+	return a.archive.list()
+}
+
+// duplicates returns the duplicate statistics, or nil if duplicates are not tracked.
+func (a *annealer) duplicates() *DuplicateStats {
+	if a.recent == nil {
+		return nil
+	}
+	d := a.recent.stats
+	return &d
+}
+
+// errorf annotates an error returned by a State at iteration i of the current run.
+func (a *annealer) errorf(i int, err error) error {
+	return fmt.Errorf("anneal: run %d, iteration %d: %w", a.runs-1, i, err)
+}
+
+// propose returns up to n neighbors of s, whose energy is e, at temperature T and their energies:
+// one if no pool is in use, and one per worker otherwise.
+// The returned slice is valid until the next call to propose.
+func (a *annealer) propose(s State, e float64, n int, T float64) []proposal {
+	if a.workers == nil {
+		r := a.samples(T)
+		a.evals += r
+		a.batch[0] = evaluate(s, T, a.choose(s, e, T), r)
+		return a.batch[:1]
+	}
+	batch := a.batch[:min(n, len(a.batch))]
+	r := a.samples(T)
+	a.evals += r * len(batch)
+	for j := range batch {
+		batch[j].move = a.choose(s, e, T)
+	}
+	a.workers.propose(s, T, r, batch)
+	return batch
+}
+
+// done reports whether the current run is complete after i iterations.
+func (a *annealer) done(i int) bool {
+	if a.stopped() {
+		return true
+	}
+	if a.duration > 0 {
+		return a.elapsed() >= a.duration
+	}
+	return i >= a.iter
+}
+
+// stopped reports whether the search should stop early
+// because the budget of energy evaluations is spent or the target energy has been reached.
+func (a *annealer) stopped() bool {
+	return a.maxEvals > 0 && a.evals >= a.maxEvals || a.target != nil && a.ebest <= *a.target
+}
+
+// remaining returns the number of iterations left in the current run after i iterations,
+// or the largest int if the run is timed and no evaluation budget applies.
+func (a *annealer) remaining(i int) int {
+	n := math.MaxInt
+	if a.duration == 0 {
+		n = a.iter - i
+	}
+	return a.budget(n)
+}
+
+// budget returns n, limited by the number of energy evaluations left in the budget.
+func (a *annealer) budget(n int) int {
+	if a.maxEvals > 0 {
+		return min(n, a.maxEvals-a.evals)
+	}
+	return n
+}
+
+// temp returns the temperature at iteration i, or at the current time if the run is timed.
+func (a *annealer) temp(i int) float64 {
+	if a.duration > 0 {
+		return a.law(float64(a.elapsed()))
+	}
+	return a.law(float64(i / a.block))
+}
+
+// law returns the temperature after x temperature levels, or x nanoseconds if the run is timed.
+// Unless Schedule.Block is greater than 1, each level is one iteration.
+func (a *annealer) law(x float64) float64 {
+	if a.cooling == CoolLundyMees {
+		return a.T0 / (1 + x/a.k)
+	}
+	return a.T0 * math.Exp(-x/a.k)
+}
+
+// elapsed returns the time elapsed in the current run.
+func (a *annealer) elapsed() time.Duration { return a.clock.Now().Sub(a.start) }
+
+// accept reports whether to adopt a State whose energy exceeds the current energy by dE at iteration i.
+func (a *annealer) accept(dE float64, i int) bool {
+	if a.prob != nil {
+		return a.rand() < a.prob(dE, a.temp(i))
+	}
+	return core.Accept(dE, a.temp(i), a.rand())
+}
+
+// evaluate proposes a neighbor of s at temperature T by the operator move, if it is not negative,
+// and estimates its energy as the mean of r evaluations.
+func evaluate(s State, T float64, move, r int) proposal {
+	snew, err := neighbor(s, T, move)
+	if err != nil {
+		return proposal{err: err, move: move}
+	}
+	e, err := meanEnergy(snew, r)
+	return proposal{s: snew, e: e, err: err, move: move}
+}
+
+// neighbor returns a neighbor of s proposed at temperature T by the operator move, if it is not negative.
+func neighbor(s State, T float64, move int) (State, error) {
+	if m, ok := s.(Mover); ok && move >= 0 {
+		return m.Move(move), nil
+	}
+	switch s := s.(type) {
+	case Tempered:
+		return s.NeighborT(T), nil
+	case FallibleNeighbor:
+		return s.NeighborErr()
+	}
+	return s.Neighbor(), nil
+}
+
+// energy returns the energy of s.
+func energy(s State) (float64, error) {
+	switch s := s.(type) {
+	case FallibleEnergy:
+		return s.EnergyErr()
+	case IntEnergy:
+		return float64(s.EnergyInt()), nil
+	}
+	return s.Energy(), nil
+}
+
+// meanEnergy returns the mean of r evaluations of the energy of s, or of one if r is less than 2.
+func meanEnergy(s State, r int) (float64, error) {
+	var sum float64
+	for k := 0; k < max(r, 1); k++ {
+		e, err := energy(s)
+		if err != nil {
+			return 0, err
+		}
+		sum += e
+	}
+	return sum / float64(max(r, 1)), nil
+}
+
+// notify calls next.OnAccept if next is an AcceptNotifier.
+func notify(prev, next State) {
+	if n, ok := next.(AcceptNotifier); ok {
+		n.OnAccept(prev, next)
+	}
+}
@@ -0,0 +1,156 @@
+package anneal
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"math/rand"
+	"slices"
+)
+
+// An Ensemble is a meta-optimizer that tunes the Schedule while it anneals: it performs a sequence of trials,
+// each a search by Run from the best State found so far, and perturbs the parameters of each trial from those of
+// the most successful trial before it, by the (1+1) evolution strategy with the one-fifth success rule.
+// A trial succeeds if it improves on the best State, and its parameters then become those from which later trials
+// are perturbed; the scale of the perturbations grows after successes and shrinks after failures.
+//
+// The parameters tuned are Schedule.Ti, the ratio of Ti to Tf, which sets the rate of cooling unless Schedule.CoolRate is set,
+// and, if the input State is a Mover and the Schedule has no Policy, the weights with which the operators are chosen,
+// which replace the adaptive choice of the Bandit. The first trial uses the Schedule as configured, with equal weights.
+// The perturbations and the choices of operators draw from a source seeded by Schedule.Seed if UseSeed is true,
+// or else from Schedule.Rand if it is not nil, so that a seeded Ensemble is reproducible.
+type Ensemble struct {
+	Trials int     // number of trials; values less than 1 mean 10
+	Sigma  float64 // initial standard deviation of the perturbations of the logarithms of the parameters; zero means 0.5
+}
+
+// A Trial describes one search of an Ensemble.
+type Trial struct {
+	Ti, Tf  float64   // temperatures of the trial's Schedule
+	Weights []float64 // probabilities with which the operators of a Mover were chosen, or nil if they were not tuned
+	Energy  float64   // energy of the best State of the trial
+	Success bool      // whether the trial improved on the best State found before it
+}
+
+// An EnsembleResult describes the outcome of an Ensemble.
+type EnsembleResult struct {
+	// Result is the Result of the last successful trial, with Evaluations, Usage, and RunUsage describing all of them.
+	// If no trial succeeded, Best is the input State.
+	Result
+
+	Schedule *Schedule // Schedule of the last successful trial, or as configured if none succeeded
+	Trials   []Trial   // outcomes of the trials, in order
+}
+
+// Run searches from s by the trials of en with the Schedule configured by opts.
+// It stops at the first error, returning the outcomes of the trials until then.
+func (en *Ensemble) Run(s State, opts ...Option) (EnsembleResult, error) {
+	sch := configure(opts)
+	if err := sch.Validate(); err != nil {
+		return EnsembleResult{}, err
+	}
+	e, err := energy(s)
+	if err != nil {
+		return EnsembleResult{}, fmt.Errorf("anneal: input State: %w", err)
+	}
+	usage := sampleUsage()
+	res := EnsembleResult{Result: Result{Best: s, Energy: e, Evaluations: 1}, Schedule: sch}
+	var peak uint64
+
+	// The parameters are tuned as logarithms: x[0] is log Ti, x[1] is log log(Ti/Tf),
+	// and the rest are the logarithms of the weights of the operators.
+	x := []float64{math.Log(sch.Ti), math.Log(math.Log(sch.Ti / sch.Tf))}
+	if m, ok := s.(Mover); ok && sch.Policy == nil {
+		x = append(x, make([]float64, m.Moves())...)
+	}
+	norm, float := rand.NormFloat64, rand.Float64
+	switch {
+	case sch.UseSeed:
+		r := newSeededRand(sch.Seed, ensembleStream)
+		norm, float = r.NormFloat64, r.Float64
+	case sch.Rand != nil:
+		norm, float = sch.Rand.NormFloat64, sch.Rand.Float64
+	}
+	sigma := cmp.Or(en.Sigma, 0.5)
+	trials := en.Trials
+	if trials < 1 {
+		trials = 10
+	}
+	for t := range trials {
+		y := slices.Clone(x)
+		if t > 0 {
+			for i := range y {
+				y[i] += sigma * norm()
+			}
+			// Keep the temperatures finite and distinct.
+			y[0] = min(max(y[0], -700), 700)
+			y[1] = min(max(y[1], math.Log(1e-3)), math.Log(700))
+		}
+		c := *sch
+		c.Ti = math.Exp(y[0])
+		c.Tf = c.Ti / math.Exp(math.Exp(y[1]))
+		trial := Trial{Ti: c.Ti, Tf: c.Tf}
+		if len(y) > 2 {
+			w := weights{p: make([]float64, len(y)-2), rand: float}
+			var sum float64
+			for i := range w.p {
+				w.p[i] = math.Exp(y[2+i])
+				sum += w.p[i]
+			}
+			for i := range w.p {
+				w.p[i] /= sum
+			}
+			c.Policy, trial.Weights = w, w.p
+		}
+
+		r, err := Run(res.Best, &c)
+		res.Evaluations += r.Evaluations
+		res.RunUsage = append(res.RunUsage, r.RunUsage...)
+		peak = max(peak, r.Usage.PeakMemory)
+		if err != nil {
+			res.Usage = sampleUsage().since(usage, peak)
+			return res, fmt.Errorf("anneal: ensemble trial %d: %w", t, err)
+		}
+		trial.Energy = r.Energy
+		if trial.Success = less(r.Best, r.Energy, res.Best, res.Energy); trial.Success {
+			evals, runUsage := res.Evaluations, res.RunUsage
+			res.Result = r
+			res.Evaluations, res.RunUsage = evals, runUsage
+			res.Schedule = &c
+			x = y
+		}
+		res.Trials = append(res.Trials, trial)
+		if t > 0 {
+			// The one-fifth rule: the scale is stable when a fifth of the trials succeed.
+			if trial.Success {
+				sigma = min(sigma*math.Exp(0.8), maxSigma)
+			} else {
+				sigma *= math.Exp(-0.2)
+			}
+		}
+	}
+	res.Usage = sampleUsage().since(usage, peak)
+	return res, nil
+}
+
+// maxSigma bounds the scale of the perturbations of an Ensemble.
+const maxSigma = 2
+
+// weights is a Policy that chooses each operator with a fixed probability.
+type weights struct {
+	p    []float64      // probability of each operator
+	rand func() float64 // source of uniform random numbers in [0, 1)
+}
+
+func (w weights) Choose(c MoveContext) int {
+	n := min(len(w.p), c.Moves)
+	u := w.rand()
+	for k, p := range w.p[:n] {
+		if u -= p; u < 0 {
+			return k
+		}
+	}
+	return n - 1
+}
+
+func (w weights) Reward(o Outcome) {}
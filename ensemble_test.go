@@ -0,0 +1,47 @@
+package anneal
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+// A walk is a Seeded Mover on the integers whose operators take steps of 1 and 5.
+type walk struct {
+	x int
+	r *rand.Rand
+}
+
+func (w *walk) Energy() float64 { return float64((w.x - 40) * (w.x - 40)) }
+
+func (w *walk) Neighbor() State { return w.Move(w.r.Intn(2)) }
+
+func (w *walk) Moves() int { return 2 }
+
+func (w *walk) Move(k int) State {
+	step := []int{1, 5}[k]
+	if w.r.Intn(2) == 0 {
+		step = -step
+	}
+	return &walk{w.x + step, w.r}
+}
+
+func (w *walk) UseRand(r *rand.Rand) { w.r = r }
+
+func TestEnsembleSeed(t *testing.T) {
+	run := func() EnsembleResult {
+		en := Ensemble{Trials: 5}
+		res, err := en.Run(&walk{}, WithIterations(1000), WithSeed(1))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+	a, b := run(), run()
+	if fmt.Sprint(a.Trials) != fmt.Sprint(b.Trials) {
+		t.Errorf("seeded Ensembles disagree:\n%+v\n%+v", a.Trials, b.Trials)
+	}
+	if a.Trials[1].Weights == nil {
+		t.Error("operator weights were not tuned")
+	}
+}
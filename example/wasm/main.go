@@ -0,0 +1,97 @@
+//go:build js && wasm
+
+// Command wasm runs simulated annealing client-side in a web browser via WebAssembly.
+// It searches for a short tour of randomly placed cities, reporting progress as the search proceeds
+// and drawing the best tour on a canvas when it finishes.
+//
+// To try it, build the program and serve this directory:
+//
+//	GOOS=js GOARCH=wasm go build -o main.wasm
+//	cp "$(go env GOROOT)/lib/wasm/wasm_exec.js" .
+//	python3 -m http.server
+//
+// and open http://localhost:8000 in a browser.
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"syscall/js"
+	"time"
+
+	"github.com/dkmccandless/anneal"
+)
+
+var (
+	document = js.Global().Get("document")
+	canvas   = document.Call("getElementById", "tour")
+	ctx      = canvas.Call("getContext", "2d")
+	status   = document.Call("getElementById", "status")
+)
+
+func main() {
+	js.Global().Set("annealTour", js.FuncOf(func(this js.Value, args []js.Value) any {
+		n, iter := args[0].Int(), args[1].Int()
+		go solve(n, iter)
+		return nil
+	}))
+	select {}
+}
+
+// solve anneals a tour of n random cities for iter iterations.
+func solve(n, iter int) {
+	xs, ys := make([]float64, n), make([]float64, n)
+	for i := range xs {
+		xs[i], ys[i] = rand.Float64(), rand.Float64()
+	}
+	p := &anneal.PermutationProblem{
+		Cost: func(tour []int) float64 {
+			var d float64
+			for i, c := range tour {
+				next := tour[(i+1)%len(tour)]
+				d += math.Hypot(xs[c]-xs[next], ys[c]-ys[next])
+			}
+			return d
+		},
+		Moves: anneal.PermReverse,
+	}
+	s := p.NewState(rand.Perm(n))
+
+	// Update the status at each progress report, then sleep briefly
+	// so that the browser can render the page.
+	sch := anneal.NewSchedule()
+	sch.Iter = iter
+	sch.Every = 10000
+	sch.Observer = anneal.ObserverFunc(func(pr anneal.Progress) {
+		status.Set("textContent", fmt.Sprintf("iteration %d, temperature %.4g, best length %.4f",
+			pr.Iter, pr.Temperature, pr.Best))
+		time.Sleep(time.Millisecond)
+	})
+	r, err := anneal.Run(s, sch)
+	if err != nil {
+		status.Set("textContent", err.Error())
+		return
+	}
+	draw(xs, ys, r.Best.(*anneal.PermutationState).Perm)
+	status.Set("textContent", fmt.Sprintf("done: best length %.4f", r.Energy))
+}
+
+// draw renders a tour on the canvas.
+func draw(xs, ys []float64, tour []int) {
+	w, h := canvas.Get("width").Float(), canvas.Get("height").Float()
+	ctx.Call("clearRect", 0, 0, w, h)
+	ctx.Call("beginPath")
+	for i, c := range append(tour, tour[0]) {
+		x, y := xs[c]*w, ys[c]*h
+		if i == 0 {
+			ctx.Call("moveTo", x, y)
+		} else {
+			ctx.Call("lineTo", x, y)
+		}
+	}
+	ctx.Call("stroke")
+	for _, c := range tour {
+		ctx.Call("fillRect", xs[c]*w-2, ys[c]*h-2, 4, 4)
+	}
+}
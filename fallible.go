@@ -0,0 +1,18 @@
+package anneal
+
+// A FallibleEnergy is a State whose energy evaluation can fail,
+// for example because it calls an external service or solver.
+type FallibleEnergy interface {
+	State
+
+	// EnergyErr returns the energy of the State, or an error if it cannot be determined.
+	EnergyErr() (float64, error)
+}
+
+// A FallibleNeighbor is a State whose neighbor selection can fail.
+type FallibleNeighbor interface {
+	State
+
+	// NeighborErr returns a neighbor of the State, or an error if none can be produced.
+	NeighborErr() (State, error)
+}
@@ -0,0 +1,96 @@
+package anneal
+
+import (
+	"math"
+	"time"
+
+	"github.com/dkmccandless/anneal/core"
+)
+
+// When States are so cheap that the dispatch in step dominates the cost of an iteration,
+// a run that uses no optional features switches to sprint, which performs up to sprintLen iterations
+// in a loop as tight as core.Anneal's. The decision is made once per run after probeLen iterations,
+// if they took less than probeCost per iteration on average.
+const (
+	probeLen  = 256
+	probeCost = time.Microsecond
+	sprintLen = 4096
+)
+
+// probe decides after probeLen iterations of the run c whether the rest of the run should sprint.
+func (a *annealer) probe(c *chain) {
+	c.probed = true
+	if !a.sprintable(c) {
+		return
+	}
+	c.sprint = time.Since(c.probeStart) < probeLen*probeCost
+}
+
+// sprintable reports whether the run c uses none of the features that sprint omits.
+func (a *annealer) sprintable(c *chain) bool {
+	switch c.s.(type) {
+	case Mover, Tempered, FallibleNeighbor, FallibleEnergy:
+		return false
+	}
+	return !c.diversify && a.mem == nil && a.workers == nil && a.duration == 0 && a.audit == 0 && a.scaleTo == 0 && a.xchg == 0 && a.hist == nil && a.rate == 0 && a.yield == 0 && a.local == nil && math.IsInf(a.scanT, -1) &&
+		a.recent == nil && a.archive == nil && a.land == nil && a.nsample == [2]int{1, 1} &&
+		a.acceptor == nil && a.prob == nil && a.better == nil && a.plateau == 0 && a.record == nil && a.replay == nil
+}
+
+// sprint advances the run c as step does, but by up to sprintLen iterations,
+// stopping early to deliver a progress report.
+func (a *annealer) sprint(c *chain) (bool, error) {
+	if a.done(c.i) {
+		if a.obs != nil {
+			a.report(c.i, a.temp(c.i), c.e, c.accepted)
+		}
+		return false, nil
+	}
+	n := min(a.remaining(c.i), sprintLen)
+	if a.obs != nil {
+		if c.i >= c.nextRpt {
+			a.report(c.i, a.temp(c.i), c.e, c.accepted)
+			c.nextRpt = c.i + a.every
+		}
+		n = min(n, c.nextRpt-c.i)
+	}
+	s, e := c.s, c.e
+	for end := c.i + n; c.i < end; {
+		T := a.temp(c.i)
+		snew := s.Neighbor()
+		var enew float64
+		if n, ok := snew.(IntEnergy); ok {
+			enew = float64(n.EnergyInt())
+		} else {
+			enew = snew.Energy()
+		}
+		a.evals++
+		if !finite(enew) {
+			if ok, err := a.admit(enew); err != nil {
+				c.s, c.e, c.f = s, e, e
+				return false, a.errorf(c.i, err)
+			} else if !ok {
+				a.recycle(snew, false)
+				c.i++
+				continue
+			}
+		}
+		c.i++
+		best := a.promote(snew, enew)
+		if best {
+			a.tbest = T / a.scale
+			if a.target != nil && a.ebest <= *a.target {
+				end = c.i
+			}
+		}
+		if dE := delta(enew, e); dE < 0 || core.Accept(dE, T, a.rand()) {
+			notify(s, snew)
+			s, e = snew, enew
+			c.accepted++
+		} else {
+			a.recycle(snew, best)
+		}
+	}
+	c.s, c.e, c.f = s, e, e
+	return true, nil
+}
@@ -0,0 +1,248 @@
+package anneal
+
+import (
+	"math/rand"
+	"sync"
+)
+
+// A GroupMove is a set of kinds of moves on an assignment of items to groups.
+type GroupMove int
+
+const (
+	GroupReassign GroupMove = 1 << iota // move one item to another group
+	GroupSwap                           // exchange the groups of two items in different groups
+	GroupMerge                          // move every item of one group into another
+	GroupSplit                          // move a random part of a group into an empty group
+
+	GroupAll = GroupReassign | GroupSwap | GroupMerge | GroupSplit
+)
+
+// A GroupingProblem describes the minimization of a cost function of an assignment of N items to K groups,
+// as in clustering, districting, and team formation. Groups may be empty.
+type GroupingProblem struct {
+	K    int                        // number of groups
+	Cost func(assign []int) float64 // cost of assigning item i to group assign[i]; must not retain or modify assign
+
+	// Delta, if not nil, returns the change in cost caused by applying changes to assign,
+	// so that a neighbor's energy is computed incrementally rather than by Cost.
+	// Each change moves a distinct item. Delta must not retain or modify its arguments.
+	Delta func(assign []int, changes []GroupChange) float64
+
+	Moves GroupMove // kinds of moves to choose among uniformly; zero means GroupAll
+}
+
+// A GroupChange moves Item from group From to group To.
+type GroupChange struct {
+	Item, From, To int
+}
+
+// NewState returns a GroupingState of p holding assign, whose elements must be in [0, p.K). It does not copy assign.
+func (p *GroupingProblem) NewState(assign []int) *GroupingState {
+	return &GroupingState{Assign: assign, p: p, e: p.Cost(assign)}
+}
+
+// Random returns a GroupingState of p assigning each of n items to a uniformly random group.
+func (p *GroupingProblem) Random(n int) *GroupingState {
+	assign := make([]int, n)
+	for i := range assign {
+		assign[i] = rand.Intn(p.K)
+	}
+	return p.NewState(assign)
+}
+
+// A GroupingState is an assignment of items to groups in the search space of a GroupingProblem.
+// Its energy is the value of the cost function, maintained incrementally if the problem has a Delta function.
+// Each neighbor differs from it by a single move chosen uniformly from the kinds the problem allows;
+// a move that is impossible, such as a merge when only one group is occupied, is replaced by a reassignment.
+// GroupingState implements Recomputer, so that an audit can detect an inconsistent Delta function,
+// Seeded, and Recycler by drawing the storage of its neighbors from a pool.
+type GroupingState struct {
+	Assign []int
+	p      *GroupingProblem
+	e      float64
+	rand   source
+}
+
+// Energy returns the cost of s.Assign.
+func (s *GroupingState) Energy() float64 { return s.e }
+
+// RecomputeEnergy returns the cost of s.Assign computed by the cost function.
+func (s *GroupingState) RecomputeEnergy() float64 { return s.p.Cost(s.Assign) }
+
+// Neighbor returns a State that differs from s by a randomly chosen move.
+func (s *GroupingState) Neighbor() State {
+	n, k := len(s.Assign), s.p.K
+	if n == 0 || k < 2 {
+		return s.with(groupStates.Get().(*GroupingState), nil)
+	}
+	kinds := s.p.kinds()
+	var changes []GroupChange
+	switch kinds[s.rand.Intn(len(kinds))] {
+	case GroupSwap:
+		changes = s.swap()
+	case GroupMerge:
+		changes = s.merge()
+	case GroupSplit:
+		changes = s.split()
+	}
+	if changes == nil {
+		i := s.rand.Intn(n)
+		from := s.Assign[i]
+		to := s.rand.Intn(k - 1)
+		if to >= from {
+			to++
+		}
+		changes = []GroupChange{{i, from, to}}
+	}
+	return s.with(groupStates.Get().(*GroupingState), changes)
+}
+
+// UseRand sets the source of randomness of s and its neighbors.
+func (s *GroupingState) UseRand(r *rand.Rand) { s.rand.r = r }
+
+// Release returns the storage of s to the pool from which Neighbor draws.
+func (s *GroupingState) Release() { groupStates.Put(s) }
+
+// groupStates holds released GroupingStates for reuse.
+var groupStates = sync.Pool{New: func() any { return new(GroupingState) }}
+
+// swap returns the changes that exchange the groups of two items in different groups, or nil if there are none.
+func (s *GroupingState) swap() []GroupChange {
+	n := len(s.Assign)
+	i := s.rand.Intn(n)
+	// Choose j uniformly among the items outside the group of i by rejection sampling, falling back to a scan.
+	for range 8 {
+		if j := s.rand.Intn(n); s.Assign[j] != s.Assign[i] {
+			return []GroupChange{{i, s.Assign[i], s.Assign[j]}, {j, s.Assign[j], s.Assign[i]}}
+		}
+	}
+	var others []int
+	for j, g := range s.Assign {
+		if g != s.Assign[i] {
+			others = append(others, j)
+		}
+	}
+	if len(others) == 0 {
+		return nil
+	}
+	j := others[s.rand.Intn(len(others))]
+	return []GroupChange{{i, s.Assign[i], s.Assign[j]}, {j, s.Assign[j], s.Assign[i]}}
+}
+
+// merge returns the changes that move every item of one occupied group into another, or nil if fewer than two are occupied.
+func (s *GroupingState) merge() []GroupChange {
+	sizes := s.sizes()
+	var occupied []int
+	for g, size := range sizes {
+		if size > 0 {
+			occupied = append(occupied, g)
+		}
+	}
+	if len(occupied) < 2 {
+		return nil
+	}
+	a := s.rand.Intn(len(occupied))
+	b := s.rand.Intn(len(occupied) - 1)
+	if b >= a {
+		b++
+	}
+	from, to := occupied[a], occupied[b]
+	changes := make([]GroupChange, 0, sizes[from])
+	for i, g := range s.Assign {
+		if g == from {
+			changes = append(changes, GroupChange{i, from, to})
+		}
+	}
+	return changes
+}
+
+// split returns the changes that move each item of a group of at least two items into an empty group
+// with probability 1/2, keeping at least one item in each, or nil if there is no such pair of groups.
+func (s *GroupingState) split() []GroupChange {
+	sizes := s.sizes()
+	var splittable, empty []int
+	for g, size := range sizes {
+		switch {
+		case size == 0:
+			empty = append(empty, g)
+		case size >= 2:
+			splittable = append(splittable, g)
+		}
+	}
+	if len(splittable) == 0 || len(empty) == 0 {
+		return nil
+	}
+	from := splittable[s.rand.Intn(len(splittable))]
+	to := empty[s.rand.Intn(len(empty))]
+	var members []int
+	for i, g := range s.Assign {
+		if g == from {
+			members = append(members, i)
+		}
+	}
+	// Fix one member to stay and one to move, and decide the rest at random.
+	s.rand.Shuffle(len(members), func(i, j int) { members[i], members[j] = members[j], members[i] })
+	changes := []GroupChange{{members[1], from, to}}
+	for _, i := range members[2:] {
+		if s.rand.Intn(2) == 0 {
+			changes = append(changes, GroupChange{i, from, to})
+		}
+	}
+	return changes
+}
+
+// sizes returns the number of items in each group.
+func (s *GroupingState) sizes() []int {
+	sizes := make([]int, s.p.K)
+	for _, g := range s.Assign {
+		sizes[g]++
+	}
+	return sizes
+}
+
+// with copies s into dst, applies changes to it, and returns it.
+func (s *GroupingState) with(dst *GroupingState, changes []GroupChange) *GroupingState {
+	dst = s.CloneInto(dst)
+	for _, c := range changes {
+		dst.Assign[c.Item] = c.To
+	}
+	switch {
+	case s.p.Delta == nil:
+		dst.e = s.p.Cost(dst.Assign)
+	case len(changes) > 0:
+		dst.e += s.p.Delta(s.Assign, changes)
+	}
+	return dst
+}
+
+// CloneInto copies s into dst, reusing the storage of dst.Assign if it is large enough, and returns dst.
+// If dst is nil, it allocates a new GroupingState. Neighbor uses it to fill released States;
+// programs that manage their own pools of States can use it likewise.
+func (s *GroupingState) CloneInto(dst *GroupingState) *GroupingState {
+	if dst == nil {
+		dst = new(GroupingState)
+	}
+	dst.Assign = append(dst.Assign[:0], s.Assign...)
+	dst.p, dst.e, dst.rand = s.p, s.e, s.rand
+	return dst
+}
+
+// kinds returns the individual kinds of moves that p allows.
+func (p *GroupingProblem) kinds() []GroupMove {
+	if m := p.Moves & GroupAll; m != 0 {
+		return groupKinds[m]
+	}
+	return groupKinds[GroupAll]
+}
+
+// groupKinds lists the individual kinds of moves in each set.
+var groupKinds = func() (t [GroupAll + 1][]GroupMove) {
+	for moves := range t {
+		for _, m := range []GroupMove{GroupReassign, GroupSwap, GroupMerge, GroupSplit} {
+			if GroupMove(moves)&m != 0 {
+				t[moves] = append(t[moves], m)
+			}
+		}
+	}
+	return t
+}()
@@ -0,0 +1,32 @@
+package anneal
+
+import "testing"
+
+func benchGrouping() *GroupingState {
+	p := &GroupingProblem{
+		K:    10,
+		Cost: func(assign []int) float64 { return float64(assign[0]) },
+		Delta: func(assign []int, changes []GroupChange) float64 {
+			return 0
+		},
+		Moves: GroupReassign,
+	}
+	return p.Random(1000)
+}
+
+func BenchmarkGroupingNeighbor(b *testing.B) {
+	s := benchGrouping()
+	b.ReportAllocs()
+	for b.Loop() {
+		s.Neighbor().(Recycler).Release()
+	}
+}
+
+func BenchmarkGroupingCloneInto(b *testing.B) {
+	s := benchGrouping()
+	dst := s.CloneInto(nil)
+	b.ReportAllocs()
+	for b.Loop() {
+		s.CloneInto(dst)
+	}
+}
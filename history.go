@@ -0,0 +1,58 @@
+package anneal
+
+// A HistoryEntry is a State adopted by a search, recorded for diagnosis; see Schedule.History.
+type HistoryEntry struct {
+	Run    int     // index of the run, counting restarts, or -1 for the finishing descent
+	Iter   int     // iteration of the run at which the State was adopted, or the number of descent steps before it
+	Energy float64 // energy of the State
+	Data   []byte  // the State as encoded by its MarshalBinary method
+}
+
+// A history retains the most recently adopted States in a ring buffer.
+type history struct {
+	ring []HistoryEntry
+	next int // index of the oldest entry once the ring is full
+	full bool
+}
+
+func newHistory(n int) *history { return &history{ring: make([]HistoryEntry, 0, n)} }
+
+// add records s, whose energy is e, as adopted at iteration i of run. States that fail to encode are not recorded.
+func (h *history) add(s State, e float64, run, i int) {
+	sn, ok := s.(Snapshotter)
+	if !ok {
+		return
+	}
+	data, err := sn.MarshalBinary()
+	if err != nil {
+		return
+	}
+	en := HistoryEntry{Run: run, Iter: i, Energy: e, Data: data}
+	if !h.full {
+		h.ring = append(h.ring, en)
+		h.full = len(h.ring) == cap(h.ring)
+		return
+	}
+	h.ring[h.next] = en
+	h.next = (h.next + 1) % len(h.ring)
+}
+
+// list returns the entries from oldest to newest.
+func (h *history) list() []HistoryEntry {
+	return append(append([]HistoryEntry(nil), h.ring[h.next:]...), h.ring[:h.next]...)
+}
+
+// adopted records s, whose energy is e, in the history, if one is kept.
+func (a *annealer) adopted(s State, e float64, run, i int) {
+	if a.hist != nil {
+		a.hist.add(s, e, run, i)
+	}
+}
+
+// historyEntries returns the recorded history, or nil if none is kept.
+func (a *annealer) historyEntries() []HistoryEntry {
+	if a.hist == nil {
+		return nil
+	}
+	return a.hist.list()
+}
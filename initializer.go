@@ -0,0 +1,103 @@
+package anneal
+
+import "fmt"
+
+// An Initializer constructs starting States, for example by a randomized construction heuristic.
+// When a Schedule lists Initializers, each restart begins from a new State constructed by one of them
+// instead of from the best State found so far.
+type Initializer interface {
+	// Initial returns a new starting State.
+	Initial() (State, error)
+}
+
+// An InitializerFunc is a function that implements Initializer.
+type InitializerFunc func() (State, error)
+
+func (f InitializerFunc) Initial() (State, error) { return f() }
+
+// InitializerStats describes the restarts that began from the States of one Initializer.
+// A restart that adopts the State of a Shared in an exchange is attributed to none.
+type InitializerStats struct {
+	Runs int // number of restarts begun from its States
+	Wins int // number of those restarts that improved on the best State found before them
+}
+
+// rate returns the success rate of the Initializer with add-one smoothing,
+// so that an Initializer that has not yet won retains a chance of being chosen.
+func (st InitializerStats) rate() float64 {
+	return float64(st.Wins+1) / float64(st.Runs+2)
+}
+
+// An initializers allocates restarts among Initializers.
+type initializers struct {
+	list  []Initializer
+	stats []InitializerStats
+}
+
+func newInitializers(list []Initializer) *initializers {
+	return &initializers{list: list, stats: make([]InitializerStats, len(list))}
+}
+
+// pick returns the index of the Initializer to use for the next restart, given a uniform random number u in [0, 1).
+// Each Initializer is used once in turn; thereafter they are chosen with probability proportional to their success rates.
+func (in *initializers) pick(u float64) int {
+	for i, st := range in.stats {
+		if st.Runs == 0 {
+			return i
+		}
+	}
+	var total float64
+	for _, st := range in.stats {
+		total += st.rate()
+	}
+	u *= total
+	for i, st := range in.stats {
+		if u -= st.rate(); u < 0 {
+			return i
+		}
+	}
+	return len(in.stats) - 1
+}
+
+// restart begins a run from a State constructed by an Initializer chosen by pick.
+// Its completion is credited to the Initializer when the run ends.
+func (a *annealer) restart(diversify bool) (*chain, error) {
+	var i int
+	if a.replay != nil {
+		var err error
+		if i, err = a.replay.pick(len(a.inits.list)); err != nil {
+			return nil, err
+		}
+	} else {
+		i = a.inits.pick(a.rand())
+		if a.record != nil {
+			a.record.write(recordPick, uint64(i))
+		}
+	}
+	s, err := a.inits.list[i].Initial()
+	if err != nil {
+		return nil, fmt.Errorf("anneal: Initializer %d: %w", i, err)
+	}
+	a.provide(s)
+	e, err := meanEnergy(s, a.nsample[0])
+	if err == nil && !admitInput(e, a.nonFinite) {
+		err = &NonFiniteError{e}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("anneal: Initializer %d: State: %w", i, err)
+	}
+	a.evals += a.nsample[0]
+	wins := a.wins
+	a.consider(s, e)
+	c := a.begin(s, e, diversify)
+	c.init, c.wins = i, wins
+	return c, nil
+}
+
+// initializerStats returns the statistics of each Initializer, or nil if none are in use.
+func (a *annealer) initializerStats() []InitializerStats {
+	if a.inits == nil {
+		return nil
+	}
+	return append([]InitializerStats(nil), a.inits.stats...)
+}
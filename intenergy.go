@@ -0,0 +1,29 @@
+package anneal
+
+// An IntEnergy is a State whose energy is an integer, as in purely combinatorial problems such as
+// counting violated constraints. Anneal calls EnergyInt in place of Energy, and when it compares States
+// to determine the best State or whether a descent improves, it compares integer energies exactly,
+// even those too large to be represented exactly by a float64. Energies are converted to float64
+// for the acceptance probability and for reporting. EnergyInt should return the same value each time
+// it is called on the same State.
+type IntEnergy interface {
+	State
+
+	// EnergyInt returns the energy of the State.
+	EnergyInt() int64
+}
+
+// maxExact is the magnitude beyond which not every integer is exactly representable as a float64.
+const maxExact = 1 << 53
+
+// less reports whether s, whose energy is e, has lower energy than t, whose energy is f.
+// Because the conversion to float64 preserves order, only equal energies can hide a difference,
+// so the integer energies of IntEnergy States are consulted only then.
+func less(s State, e float64, t State, f float64) bool {
+	if e != f || e > -maxExact && e < maxExact {
+		return e < f
+	}
+	si, ok := s.(IntEnergy)
+	ti, tok := t.(IntEnergy)
+	return ok && tok && si.EnergyInt() < ti.EnergyInt()
+}
@@ -0,0 +1,108 @@
+/*
+Package ising represents Ising spin glasses and anneals them with simulated quantum annealing.
+
+A Model assigns to each configuration of spins s_i = ±1 the energy
+
+	E(s) = Σ_i H_i s_i + Σ_(i,j) J_ij s_i s_j
+
+summed over the biases H and the couplings J. This is the convention of quantum annealing hardware,
+so results are directly comparable with those reported for it.
+*/
+package ising
+
+// A Coupling is an interaction of strength V between spins I and J.
+type Coupling struct {
+	I, J int
+	V    float64
+}
+
+// A Model is an Ising model. It is immutable after construction.
+type Model struct {
+	h   []float64
+	adj []edge // adj[off[i]:off[i+1]] lists the couplings of spin i
+	off []int
+}
+
+// An edge is a coupling as seen from one of its spins.
+type edge struct {
+	j int
+	v float64
+}
+
+// NewModel returns a Model with biases h and the given couplings, which must refer to spins in [0, len(h)).
+// Couplings between the same pair of spins are summed, and couplings of a spin with itself contribute a constant and are ignored.
+func NewModel(h []float64, couplings []Coupling) *Model {
+	m := &Model{h: append([]float64(nil), h...), off: make([]int, len(h)+1)}
+	// Count the couplings of each spin, then place them contiguously in order of spin.
+	for _, c := range couplings {
+		if c.I != c.J {
+			m.off[c.I+1]++
+			m.off[c.J+1]++
+		}
+	}
+	for i := range h {
+		m.off[i+1] += m.off[i]
+	}
+	m.adj = make([]edge, m.off[len(h)])
+	next := append([]int(nil), m.off[:len(h)]...)
+	for _, c := range couplings {
+		if c.I == c.J {
+			continue
+		}
+		m.adj[next[c.I]] = edge{c.J, c.V}
+		m.adj[next[c.J]] = edge{c.I, c.V}
+		next[c.I]++
+		next[c.J]++
+	}
+	return m
+}
+
+// edges returns the couplings of spin i.
+func (m *Model) edges(i int) []edge { return m.adj[m.off[i]:m.off[i+1]] }
+
+// Len returns the number of spins.
+func (m *Model) Len() int { return len(m.h) }
+
+// Energy returns the energy of the configuration s, whose elements are +1 or -1.
+func (m *Model) Energy(s []int8) float64 {
+	var e float64
+	for i, h := range m.h {
+		e += h * float64(s[i])
+		for _, ed := range m.edges(i) {
+			if ed.j > i {
+				e += ed.v * float64(s[i]) * float64(s[ed.j])
+			}
+		}
+	}
+	return e
+}
+
+// Field returns the local field at spin i in configuration s: H_i + Σ_j J_ij s_j.
+func (m *Model) Field(s []int8, i int) float64 {
+	f := m.h[i]
+	for _, ed := range m.edges(i) {
+		f += ed.v * float64(s[ed.j])
+	}
+	return f
+}
+
+// FlipDelta returns the change in energy caused by flipping spin i of configuration s.
+func (m *Model) FlipDelta(s []int8, i int) float64 {
+	return -2 * float64(s[i]) * m.Field(s, i)
+}
+
+// Biases returns a copy of the biases H.
+func (m *Model) Biases() []float64 { return append([]float64(nil), m.h...) }
+
+// Couplings returns the couplings of m, listing each pair of spins once with I < J.
+func (m *Model) Couplings() []Coupling {
+	var cs []Coupling
+	for i := range m.h {
+		for _, ed := range m.edges(i) {
+			if ed.j > i {
+				cs = append(cs, Coupling{i, ed.j, ed.v})
+			}
+		}
+	}
+	return cs
+}
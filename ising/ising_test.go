@@ -0,0 +1,72 @@
+package ising
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestModel(t *testing.T) {
+	const n = 20
+	h := make([]float64, n)
+	for i := range h {
+		h[i] = rand.NormFloat64()
+	}
+	var couplings []Coupling
+	for range 50 {
+		i, j := rand.Intn(n), rand.Intn(n)
+		couplings = append(couplings, Coupling{min(i, j), max(i, j), rand.NormFloat64()})
+	}
+	m := NewModel(h, couplings)
+
+	s := make([]int8, n)
+	for i := range s {
+		s[i] = int8(2*rand.Intn(2) - 1)
+	}
+	// Compute the energy directly from the arguments to NewModel.
+	want := 0.0
+	for i, hi := range h {
+		want += hi * float64(s[i])
+	}
+	for _, c := range couplings {
+		if c.I != c.J {
+			want += c.V * float64(s[c.I]) * float64(s[c.J])
+		}
+	}
+	if e := m.Energy(s); !near(e, want) {
+		t.Fatalf("Energy = %v, want %v", e, want)
+	}
+	for i := range s {
+		d := m.FlipDelta(s, i)
+		s[i] = -s[i]
+		if e := m.Energy(s); !near(e, want+d) {
+			t.Fatalf("FlipDelta(%d) = %v, want %v", i, d, e-want)
+		}
+		want += d
+	}
+
+	var wantCouplings []Coupling
+	for _, c := range couplings {
+		if c.I != c.J {
+			wantCouplings = append(wantCouplings, c)
+		}
+	}
+	got := m.Couplings()
+	sortCouplings(got)
+	sortCouplings(wantCouplings)
+	if !slices.Equal(got, wantCouplings) {
+		t.Errorf("Couplings = %v, want %v", got, wantCouplings)
+	}
+}
+
+func sortCouplings(cs []Coupling) {
+	slices.SortStableFunc(cs, func(a, b Coupling) int {
+		if a.I != b.I {
+			return a.I - b.I
+		}
+		return a.J - b.J
+	})
+}
+
+func near(a, b float64) bool { return math.Abs(a-b) < 1e-9 }
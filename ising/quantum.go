@@ -0,0 +1,93 @@
+package ising
+
+import (
+	"math"
+	"math/rand"
+)
+
+// A QuantumSchedule controls simulated quantum annealing.
+type QuantumSchedule struct {
+	Sweeps  int     // number of Monte Carlo sweeps, each attempting to flip every spin of every replica once
+	Trotter int     // number of Trotter replicas P
+	T       float64 // temperature, held fixed
+	Gamma0  float64 // initial transverse field
+	Gamma1  float64 // final transverse field; the field decreases linearly from Gamma0 to Gamma1
+}
+
+// NewQuantumSchedule returns a QuantumSchedule populated with default values.
+func NewQuantumSchedule() QuantumSchedule {
+	return QuantumSchedule{
+		Sweeps:  1000,
+		Trotter: 20,
+		T:       0.05,
+		Gamma0:  3,
+		Gamma1:  1e-3,
+	}
+}
+
+// Quantum performs simulated quantum annealing by path-integral Monte Carlo and returns the lowest-energy
+// configuration found in any replica, together with its energy.
+//
+// The quantum system with Hamiltonian E(s) - Γ Σ_i σ^x_i at temperature T is mapped onto P coupled classical replicas
+// s_1, ..., s_P of the Model arranged in a ring along the imaginary-time direction, with effective energy
+//
+//	Σ_k E(s_k)/P - J⊥ Σ_k Σ_i s_k,i s_k+1,i,  J⊥ = -(T/2) ln tanh(Γ/(P T)).
+//
+// Each sweep attempts Metropolis flips of every spin in every replica at temperature T, then decreases Γ.
+// As Γ shrinks, the replicas couple ever more strongly and the system localizes in low-energy configurations,
+// tunneling through barriers that thermal annealing would have to climb.
+//
+// Every replica starts from init, or from a uniformly random configuration if init is nil.
+// Random numbers are drawn from r, or from the global source if r is nil.
+func (m *Model) Quantum(init []int8, sch QuantumSchedule, r *rand.Rand) ([]int8, float64) {
+	float := rand.Float64
+	intn := rand.Intn
+	if r != nil {
+		float, intn = r.Float64, r.Intn
+	}
+	n, p := m.Len(), max(sch.Trotter, 1)
+	if init == nil {
+		init = make([]int8, n)
+		for i := range init {
+			init[i] = int8(2*intn(2) - 1)
+		}
+	}
+	// The replicas are consecutive slices of a single buffer.
+	spins := make([]int8, n*p)
+	replicas := make([][]int8, p)
+	energies := make([]float64, p)
+	for k := range replicas {
+		replicas[k] = spins[k*n : (k+1)*n : (k+1)*n]
+		copy(replicas[k], init)
+		energies[k] = m.Energy(replicas[k])
+	}
+	best, ebest := append([]int8(nil), init...), energies[0]
+
+	for sweep := 0; sweep < sch.Sweeps; sweep++ {
+		gamma := sch.Gamma0
+		if sch.Sweeps > 1 {
+			gamma += (sch.Gamma1 - sch.Gamma0) * float64(sweep) / float64(sch.Sweeps-1)
+		}
+		jperp := -sch.T / 2 * math.Log(math.Tanh(gamma/(float64(p)*sch.T)))
+		for k, s := range replicas {
+			prev, next := replicas[(k+p-1)%p], replicas[(k+1)%p]
+			for i := range s {
+				dE := m.FlipDelta(s, i)
+				// With a single replica there is no imaginary-time coupling.
+				var dK float64
+				if p > 1 {
+					dK = 2 * jperp * float64(s[i]) * float64(prev[i]+next[i])
+				}
+				if d := dE/float64(p) + dK; d <= 0 || float() < math.Exp(-d/sch.T) {
+					s[i] = -s[i]
+					energies[k] += dE
+				}
+			}
+			if energies[k] < ebest {
+				copy(best, s)
+				ebest = energies[k]
+			}
+		}
+	}
+	return best, ebest
+}
@@ -0,0 +1,83 @@
+package anneal
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// LandscapeHeader begins every landscape log written for Schedule.Landscape.
+// It is followed by records of LandscapeRecordSize bytes, each holding in little-endian order
+// the hash of an evaluated State (uint64), the hash of the State from which it was proposed (uint64),
+// its energy (IEEE 754 float64), and a flags byte whose bit 0 is set if the State was adopted
+// and bit 1 if it was considered for adoption rather than discarded unconsidered from a batch.
+// Package landscape reads and analyzes such logs.
+const LandscapeHeader = "anneal-landscape-1\n"
+
+// LandscapeRecordSize is the size in bytes of a record in a landscape log.
+const LandscapeRecordSize = 25
+
+// Flags of a landscape log record.
+const (
+	LandscapeAccepted   = 1 << 0
+	LandscapeConsidered = 1 << 1
+)
+
+// landscapeFlag returns LandscapeAccepted if accepted is true and 0 otherwise.
+func landscapeFlag(accepted bool) byte {
+	if accepted {
+		return LandscapeAccepted
+	}
+	return 0
+}
+
+// A landLog writes a landscape log.
+type landLog struct {
+	w   *bufio.Writer
+	buf [LandscapeRecordSize]byte
+}
+
+func newLandLog(w io.Writer) *landLog {
+	l := &landLog{w: bufio.NewWriter(w)}
+	l.w.WriteString(LandscapeHeader)
+	return l
+}
+
+// log records the evaluation of s, whose energy is e, proposed from parent.
+// States that do not implement Hasher are not recorded.
+func (a *annealer) log(parent, s State, e float64, flags byte) {
+	if a.land == nil || s == nil {
+		return
+	}
+	h, ok := s.(Hasher)
+	p, pok := parent.(Hasher)
+	if !ok || !pok {
+		return
+	}
+	l := a.land
+	binary.LittleEndian.PutUint64(l.buf[0:], h.Hash())
+	binary.LittleEndian.PutUint64(l.buf[8:], p.Hash())
+	binary.LittleEndian.PutUint64(l.buf[16:], math.Float64bits(e))
+	l.buf[24] = flags
+	l.w.Write(l.buf[:])
+}
+
+// flush writes any buffered records of the landscape and decision logs
+// and returns the first error encountered in writing each.
+func (a *annealer) flush() error {
+	var errs []error
+	if a.land != nil {
+		if err := a.land.w.Flush(); err != nil {
+			errs = append(errs, fmt.Errorf("anneal: landscape log: %w", err))
+		}
+	}
+	if a.record != nil {
+		if err := a.record.w.Flush(); err != nil {
+			errs = append(errs, fmt.Errorf("anneal: decision log: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,194 @@
+/*
+Package landscape analyzes the energy landscapes explored by package anneal.
+
+A landscape log, written by anneal when Schedule.Landscape is set, records every State evaluated during a search
+by its hash, together with its energy, the State from which it was proposed, and whether it was adopted.
+Read decodes a log, and a Graph assembled from its records describes the States and transitions observed:
+which States the search visited, how often it moved between them, and the basins of attraction
+into which the observed States fall. Because only States that the search evaluated are known,
+the results describe the sampled part of the landscape, not the whole of it.
+*/
+package landscape
+
+import (
+	"bufio"
+	"cmp"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+
+	"github.com/dkmccandless/anneal"
+)
+
+// A Record is the evaluation of one State.
+type Record struct {
+	Hash       uint64  // hash of the evaluated State
+	Parent     uint64  // hash of the State from which it was proposed
+	Energy     float64 // energy of the evaluated State
+	Accepted   bool    // whether the search adopted the State
+	Considered bool    // whether the search considered the State for adoption
+}
+
+// Read decodes the landscape log read from r.
+func Read(r io.Reader) ([]Record, error) {
+	br := bufio.NewReader(r)
+	header := make([]byte, len(anneal.LandscapeHeader))
+	if _, err := io.ReadFull(br, header); err != nil || string(header) != anneal.LandscapeHeader {
+		return nil, errors.New("landscape: not a landscape log")
+	}
+	var (
+		records []Record
+		buf     [anneal.LandscapeRecordSize]byte
+	)
+	for {
+		if _, err := io.ReadFull(br, buf[:]); err == io.EOF {
+			return records, nil
+		} else if err != nil {
+			return records, fmt.Errorf("landscape: record %d: %w", len(records), err)
+		}
+		records = append(records, Record{
+			Hash:       binary.LittleEndian.Uint64(buf[0:]),
+			Parent:     binary.LittleEndian.Uint64(buf[8:]),
+			Energy:     math.Float64frombits(binary.LittleEndian.Uint64(buf[16:])),
+			Accepted:   buf[24]&anneal.LandscapeAccepted != 0,
+			Considered: buf[24]&anneal.LandscapeConsidered != 0,
+		})
+	}
+}
+
+// A Node is a State observed in a landscape log.
+type Node struct {
+	Hash        uint64
+	Energy      float64 // energy of the State, or NaN if it was never evaluated, as for the input State
+	Evaluations int     // number of times the State was evaluated
+	Adoptions   int     // number of times the search adopted the State
+}
+
+// An Edge is a transition from one State to a neighbor proposed from it.
+type Edge struct {
+	From, To  uint64
+	Proposals int // number of times To was proposed from From
+	Accepted  int // number of those proposals that were adopted
+}
+
+// A Graph is the set of States and transitions observed in a landscape log.
+type Graph struct {
+	Nodes map[uint64]*Node
+	Edges map[[2]uint64]*Edge // keyed by {From, To}
+
+	adj map[uint64][]uint64 // neighbors of each State in either direction
+}
+
+// NewGraph returns the Graph of the States and transitions in records.
+func NewGraph(records []Record) *Graph {
+	g := &Graph{Nodes: make(map[uint64]*Node), Edges: make(map[[2]uint64]*Edge), adj: make(map[uint64][]uint64)}
+	for _, r := range records {
+		n := g.node(r.Hash)
+		n.Energy = r.Energy
+		n.Evaluations++
+		if r.Accepted {
+			n.Adoptions++
+		}
+		g.node(r.Parent)
+		k := [2]uint64{r.Parent, r.Hash}
+		e, ok := g.Edges[k]
+		if !ok {
+			e = &Edge{From: r.Parent, To: r.Hash}
+			g.Edges[k] = e
+			g.adj[r.Parent] = append(g.adj[r.Parent], r.Hash)
+			g.adj[r.Hash] = append(g.adj[r.Hash], r.Parent)
+		}
+		e.Proposals++
+		if r.Accepted {
+			e.Accepted++
+		}
+	}
+	return g
+}
+
+func (g *Graph) node(h uint64) *Node {
+	n, ok := g.Nodes[h]
+	if !ok {
+		n = &Node{Hash: h, Energy: math.NaN()}
+		g.Nodes[h] = n
+	}
+	return n
+}
+
+// A Basin is a set of observed States that descend to the same local minimum.
+type Basin struct {
+	Minimum uint64  // hash of the local minimum
+	Energy  float64 // energy of the local minimum
+	Size    int     // number of States in the basin, including the minimum
+	Visits  int     // number of adoptions of States in the basin
+}
+
+// Basins assigns every State of known energy to the basin of the local minimum reached from it by steepest descent
+// along observed transitions, in either direction, and returns the basins in order of increasing energy.
+// A local minimum is a State none of whose observed neighbors has lower energy.
+func (g *Graph) Basins() []Basin {
+	down := make(map[uint64]uint64) // lowest neighbor of each State, if lower than the State
+	for h, n := range g.Nodes {
+		if math.IsNaN(n.Energy) {
+			continue
+		}
+		best, e := h, n.Energy
+		for _, m := range g.adj[h] {
+			if me := g.Nodes[m].Energy; me < e {
+				best, e = m, me
+			}
+		}
+		down[h] = best
+	}
+	basins := make(map[uint64]*Basin)
+	for h := range down {
+		m := h
+		for down[m] != m {
+			m = down[m]
+		}
+		b, ok := basins[m]
+		if !ok {
+			b = &Basin{Minimum: m, Energy: g.Nodes[m].Energy}
+			basins[m] = b
+		}
+		b.Size++
+		b.Visits += g.Nodes[h].Adoptions
+	}
+	list := make([]Basin, 0, len(basins))
+	for _, b := range basins {
+		list = append(list, *b)
+	}
+	slices.SortFunc(list, func(a, b Basin) int {
+		return cmp.Or(cmp.Compare(a.Energy, b.Energy), cmp.Compare(a.Minimum, b.Minimum))
+	})
+	return list
+}
+
+// WriteDOT writes the transitions that the search adopted to w in the DOT language of Graphviz,
+// labeling each State with its energy and each transition with the number of adoptions.
+func (g *Graph) WriteDOT(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "digraph landscape {")
+	keys := make([][2]uint64, 0, len(g.Edges))
+	for k, e := range g.Edges {
+		if e.Accepted > 0 {
+			keys = append(keys, k)
+		}
+	}
+	slices.SortFunc(keys, func(a, b [2]uint64) int { return cmp.Or(cmp.Compare(a[0], b[0]), cmp.Compare(a[1], b[1])) })
+	seen := make(map[uint64]bool)
+	for _, k := range keys {
+		for _, h := range k {
+			if !seen[h] {
+				seen[h] = true
+				fmt.Fprintf(bw, "\tn%x [label=\"%v\"];\n", h, g.Nodes[h].Energy)
+			}
+		}
+		fmt.Fprintf(bw, "\tn%x -> n%x [label=\"%d\"];\n", k[0], k[1], g.Edges[k].Accepted)
+	}
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
@@ -0,0 +1,50 @@
+package anneal
+
+import "fmt"
+
+// A LocalSearch improves States by a problem-specific procedure, such as 2-opt or Lin–Kernighan moves
+// for a tour, so that annealing can be hybridized with it in the manner of memetic algorithms; see Schedule.LocalSearch.
+type LocalSearch interface {
+	// Improve returns a State found by a local search from s of at most budget steps, whatever a step means
+	// to the procedure, or nil if it found no improvement. It must not modify s.
+	// The bound guards the search against procedures that would otherwise run indefinitely;
+	// Anneal also adopts the result only if its energy is lower, so that a faulty procedure cannot make matters worse.
+	// An error ends the search.
+	Improve(s State, budget int) (State, error)
+}
+
+// A LocalSearchFunc is a function that implements LocalSearch.
+type LocalSearchFunc func(s State, budget int) (State, error)
+
+func (f LocalSearchFunc) Improve(s State, budget int) (State, error) { return f(s, budget) }
+
+// defaultLocalBudget is the budget of a LocalSearch when Schedule.LocalBudget is zero.
+const defaultLocalBudget = 1000
+
+// improve applies the LocalSearch to the current State of the run c and adopts the result if it has lower energy.
+// Evaluations by the procedure itself are not counted.
+func (a *annealer) improve(c *chain) error {
+	t, err := a.local.Improve(c.s, a.localN)
+	if err != nil {
+		return fmt.Errorf("local search: %w", err)
+	}
+	if t == nil {
+		return nil
+	}
+	e, err := energy(t)
+	a.evals++
+	if err != nil {
+		return fmt.Errorf("local search: %w", err)
+	}
+	if !finite(e) || !less(t, e, c.s, c.e) {
+		return nil
+	}
+	notify(c.s, t)
+	c.s, c.e, c.f = t, e, e
+	if c.diversify {
+		c.f += a.mem.penalty(t)
+	}
+	a.consider(t, e)
+	a.adopted(t, e, a.runs-1, c.i)
+	return nil
+}
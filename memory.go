@@ -0,0 +1,49 @@
+package anneal
+
+// A Componenter is a State that is composed of discrete solution attributes,
+// such as the edges of a tour or the assignments of items to bins.
+// Anneal uses the components of adopted States as long-term memory to diversify the search during restarts.
+type Componenter interface {
+	State
+
+	// Components returns identifiers of the attributes present in the State.
+	// Equal identifiers must denote the same attribute in all States.
+	Components() []int
+}
+
+// A memory records how often each component appears in adopted States.
+type memory struct {
+	weight float64
+	count  map[int]int
+	n      int // number of States recorded
+}
+
+func newMemory(weight float64) *memory {
+	return &memory{weight: weight, count: make(map[int]int)}
+}
+
+// record adds the components of s to the memory.
+func (m *memory) record(s State) {
+	c, ok := s.(Componenter)
+	if !ok {
+		return
+	}
+	for _, id := range c.Components() {
+		m.count[id]++
+	}
+	m.n++
+}
+
+// penalty returns the diversification term for s: the weighted sum over the components of s
+// of the fraction of recorded States in which each appeared.
+func (m *memory) penalty(s State) float64 {
+	c, ok := s.(Componenter)
+	if !ok || m.n == 0 {
+		return 0
+	}
+	var sum int
+	for _, id := range c.Components() {
+		sum += m.count[id]
+	}
+	return m.weight * float64(sum) / float64(m.n)
+}
@@ -0,0 +1,43 @@
+package anneal
+
+import (
+	"maps"
+	"math/rand"
+	"testing"
+)
+
+// A seededLine is a Seeded Componenter on the integers with energy x^2 and neighbors x ± 1,
+// whose only component is x.
+type seededLine struct {
+	x int
+	r *rand.Rand
+}
+
+func (l *seededLine) Energy() float64 { return float64(l.x * l.x) }
+
+func (l *seededLine) Neighbor() State { return &seededLine{l.x + 2*l.r.Intn(2) - 1, l.r} }
+
+func (l *seededLine) Components() []int { return []int{l.x} }
+
+func (l *seededLine) UseRand(r *rand.Rand) { l.r = r }
+
+func TestMemoryFastPath(t *testing.T) {
+	// The first run of a diversified search is not itself diversified, but it must record its States
+	// in the memory whether or not it could have sprinted. An audit prevents sprinting without altering the search.
+	memory := func(opts ...Option) *memory {
+		sch := configure(append([]Option{WithIterations(10000), WithRestarts(1, 1), WithSeed(1)}, opts...))
+		s := &seededLine{x: 50}
+		a := newAnnealer(s, s.Energy(), sch)
+		if err := a.run(s, s.Energy(), false); err != nil {
+			t.Fatal(err)
+		}
+		return a.mem
+	}
+	fast, slow := memory(), memory(WithAudit(1<<30))
+	if fast.n != slow.n || !maps.Equal(fast.count, slow.count) {
+		t.Errorf("memory recorded %d States %v, want %d States %v", fast.n, fast.count, slow.n, slow.count)
+	}
+	if slow.n < 2 {
+		t.Errorf("memory recorded %d States", slow.n)
+	}
+}
@@ -0,0 +1,79 @@
+package anneal
+
+import (
+	"expvar"
+	"sync"
+)
+
+// Metrics is an Observer that publishes the progress of annealing runs as expvar variables,
+// so that services embedding the annealer can monitor it with existing tooling.
+// Systems such as Prometheus can collect the variables from the expvar handler.
+//
+// The published map contains the following variables:
+//
+//	iterations       total number of iterations observed
+//	iterations_rate  iterations per second between the two most recent reports
+//	temperature      current temperature
+//	energy           energy of the current State
+//	best_energy      energy of the best State
+//	acceptance_ratio fraction of proposals adopted between the two most recent reports
+//	labels           object holding the labels of the search; see Schedule.Labels
+//
+// A Metrics may be shared by sequential runs, in which case the variables describe the most recent report.
+type Metrics struct {
+	vars                             *expvar.Map
+	iterations                       expvar.Int
+	rate, temp, energy, best, accept expvar.Float
+
+	mu   sync.Mutex
+	last Progress
+}
+
+// NewMetrics returns a Metrics that publishes its variables in an expvar.Map with the given name.
+// Like expvar.Publish, it panics if the name is already in use.
+func NewMetrics(name string) *Metrics {
+	m := &Metrics{vars: expvar.NewMap(name)}
+	m.vars.Set("iterations", &m.iterations)
+	m.vars.Set("iterations_rate", &m.rate)
+	m.vars.Set("temperature", &m.temp)
+	m.vars.Set("energy", &m.energy)
+	m.vars.Set("best_energy", &m.best)
+	m.vars.Set("acceptance_ratio", &m.accept)
+	m.vars.Set("labels", expvar.Func(m.labels))
+	return m
+}
+
+// Map returns the expvar.Map in which m publishes its variables.
+func (m *Metrics) Map() *expvar.Map { return m.vars }
+
+// labels returns the labels of the most recent report.
+func (m *Metrics) labels() any {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.last.Labels == nil {
+		return map[string]string{}
+	}
+	return m.last.Labels
+}
+
+// Observe updates the variables from p.
+func (m *Metrics) Observe(p Progress) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	last := m.last
+	if p.Run != last.Run || p.Iter < last.Iter {
+		// A new run has begun.
+		last = Progress{Run: p.Run}
+	}
+	if di := p.Iter - last.Iter; di > 0 {
+		m.iterations.Add(int64(di))
+		if dt := p.Elapsed - last.Elapsed; dt > 0 {
+			m.rate.Set(float64(di) / dt.Seconds())
+		}
+		m.accept.Set(float64(p.Accepted-last.Accepted) / float64(di))
+	}
+	m.temp.Set(p.Temperature)
+	m.energy.Set(p.Energy)
+	m.best.Set(p.Best)
+	m.last = p
+}
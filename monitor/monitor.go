@@ -0,0 +1,212 @@
+/*
+Package monitor serves the live progress of annealing searches over HTTP, in the manner of net/http/pprof,
+so that long-running searches inside services can be inspected while they run.
+
+A Monitor is an anneal.Observer that remembers the most recent report and a sampled trace of the energies reported so far.
+It is also an http.Handler that serves them as a small HTML page, or as JSON if the request's format query parameter
+is "json" or its Accept header asks for application/json:
+
+	m := monitor.New(0)
+	http.Handle("/debug/anneal", m)
+	best, err := anneal.Anneal(s, anneal.WithObserver(m, 1000))
+*/
+package monitor
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"math"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dkmccandless/anneal"
+)
+
+// DefaultTraceLen is the number of points that a Monitor created by New(0) retains.
+const DefaultTraceLen = 512
+
+// A Point is a sample of the trace of a search.
+type Point struct {
+	Run         int     `json:"run"`
+	Iter        int     `json:"iter"`
+	Temperature Number  `json:"temperature"`
+	Energy      Number  `json:"energy"`
+	Best        Number  `json:"best"`
+	Elapsed     float64 `json:"elapsed"` // seconds elapsed in the run
+}
+
+// A Status is the state of a search as served by a Monitor.
+type Status struct {
+	Reports   int               `json:"reports"` // number of progress reports observed
+	Updated   time.Time         `json:"updated"` // time of the most recent report, by the system clock
+	Run       int               `json:"run"`
+	Iter      int               `json:"iter"`
+	Accepted  int               `json:"accepted"`
+	Remaining float64           `json:"remaining"` // estimated seconds remaining in the run, or 0 if unknown
+	Labels    map[string]string `json:"labels,omitempty"`
+
+	Temperature Number `json:"temperature"`
+	Energy      Number `json:"energy"`
+	Best        Number `json:"best"`
+
+	// Trace samples the reports observed, in order. While its capacity lasts it holds every report;
+	// then it keeps every other point and halves the rate at which it samples later reports, and so on,
+	// so that it always spans the whole search at a uniform stride.
+	Trace []Point `json:"trace"`
+}
+
+// A Number is a float64 that is encoded in JSON as null if it is NaN or infinite, which JSON cannot represent.
+type Number float64
+
+func (x Number) MarshalJSON() ([]byte, error) {
+	if math.IsInf(float64(x), 0) || math.IsNaN(float64(x)) {
+		return []byte("null"), nil
+	}
+	return json.Marshal(float64(x))
+}
+
+// A Monitor records the progress of a search and serves it over HTTP.
+// It is safe for concurrent use; a Monitor shared by concurrent searches interleaves their reports.
+type Monitor struct {
+	mu     sync.Mutex
+	status Status
+	n      int // capacity of the trace
+	stride int // number of reports per point in the trace
+	skip   int // number of reports since the last point
+}
+
+// New returns a Monitor whose trace retains up to n points, or DefaultTraceLen if n is not positive.
+func New(n int) *Monitor {
+	if n <= 0 {
+		n = DefaultTraceLen
+	}
+	return &Monitor{n: max(n, 2), stride: 1}
+}
+
+// Observe records p.
+func (m *Monitor) Observe(p anneal.Progress) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	st := &m.status
+	st.Reports++
+	st.Updated = time.Now()
+	st.Run, st.Iter, st.Accepted = p.Run, p.Iter, p.Accepted
+	st.Remaining = p.Remaining.Seconds()
+	st.Labels = p.Labels
+	st.Temperature, st.Energy, st.Best = Number(p.Temperature), Number(p.Energy), Number(p.Best)
+	if m.skip++; m.skip < m.stride {
+		return
+	}
+	m.skip = 0
+	st.Trace = append(st.Trace, Point{
+		Run:         p.Run,
+		Iter:        p.Iter,
+		Temperature: Number(p.Temperature),
+		Energy:      Number(p.Energy),
+		Best:        Number(p.Best),
+		Elapsed:     p.Elapsed.Seconds(),
+	})
+	if len(st.Trace) == m.n {
+		for i := range m.n / 2 {
+			st.Trace[i] = st.Trace[2*i+1]
+		}
+		st.Trace = st.Trace[:m.n/2]
+		m.stride *= 2
+	}
+}
+
+// Status returns the recorded state of the search.
+func (m *Monitor) Status() Status {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	st := m.status
+	st.Trace = append([]Point(nil), st.Trace...)
+	return st
+}
+
+// ServeHTTP serves the recorded state of the search as HTML, or as JSON if the request asks for it.
+func (m *Monitor) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	st := m.Status()
+	if r.URL.Query().Get("format") == "json" || strings.Contains(r.Header.Get("Accept"), "application/json") {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(st); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := page.Execute(w, struct {
+		Status
+		Chart template.HTML
+	}{st, chart(st.Trace)}); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// Dimensions of the chart drawn by chart.
+const chartW, chartH = 600, 200
+
+// chart returns an SVG plot of the current and best energies of the trace.
+func chart(trace []Point) template.HTML {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, p := range trace {
+		for _, e := range []Number{p.Energy, p.Best} {
+			if e := float64(e); !math.IsInf(e, 0) && !math.IsNaN(e) {
+				lo, hi = min(lo, e), max(hi, e)
+			}
+		}
+	}
+	if len(trace) < 2 || lo > hi {
+		return ""
+	}
+	if lo == hi {
+		lo, hi = lo-1, hi+1
+	}
+	line := func(e func(Point) Number, color string) string {
+		var b strings.Builder
+		for i, p := range trace {
+			y := float64(e(p))
+			if math.IsInf(y, 0) || math.IsNaN(y) {
+				continue
+			}
+			fmt.Fprintf(&b, "%.1f,%.1f ", float64(i)*chartW/float64(len(trace)-1), chartH-(y-lo)*chartH/(hi-lo))
+		}
+		return fmt.Sprintf(`<polyline fill="none" stroke="%s" points="%s"/>`, color, b.String())
+	}
+	return template.HTML(fmt.Sprintf(`<svg width="%d" height="%d" viewBox="-5 -5 %d %d">%s%s</svg>`,
+		chartW, chartH, chartW+10, chartH+10,
+		line(func(p Point) Number { return p.Energy }, "steelblue"),
+		line(func(p Point) Number { return p.Best }, "firebrick")))
+}
+
+var page = template.Must(template.New("monitor").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta http-equiv="refresh" content="2">
+<title>anneal</title>
+<style>body { font-family: sans-serif; } td { padding: 0 1em 0 0; }</style>
+</head>
+<body>
+<h1>anneal</h1>
+{{if not .Reports}}<p>No progress reported yet.</p>{{else}}
+<table>
+{{range $k, $v := .Labels}}<tr><td>{{$k}}</td><td>{{$v}}</td></tr>
+{{end}}<tr><td>run</td><td>{{.Run}}</td></tr>
+<tr><td>iteration</td><td>{{.Iter}}</td></tr>
+<tr><td>temperature</td><td>{{.Temperature}}</td></tr>
+<tr><td>energy</td><td>{{.Energy}}</td></tr>
+<tr><td>best energy</td><td>{{.Best}}</td></tr>
+<tr><td>accepted</td><td>{{.Accepted}}</td></tr>
+<tr><td>remaining</td><td>{{printf "%.1f" .Remaining}} s</td></tr>
+<tr><td>updated</td><td>{{.Updated.Format "15:04:05"}}</td></tr>
+</table>
+{{.Chart}}
+<p>Energy of the current State in blue and of the best State in red. <a href="?format=json">JSON</a></p>
+{{end}}
+</body>
+</html>
+`))
@@ -0,0 +1,190 @@
+package anneal
+
+import "math"
+
+// A Mover is a State with several kinds of moves, or operators, from which a Policy chooses each proposal.
+// Anneal calls Move in place of Neighbor, and Result.Moves reports how each operator fared.
+type Mover interface {
+	State
+
+	// Moves returns the number of operators, which must be positive and the same for every State.
+	Moves() int
+
+	// Move returns a neighbor of the State produced by operator k, where 0 <= k < Moves().
+	Move(k int) State
+}
+
+// A MoveNamer is a Mover that names its operators, such as "swap", "insert", and "reverse",
+// for the statistics reported in Result.Moves.
+type MoveNamer interface {
+	Mover
+
+	// MoveName returns the name of operator k.
+	MoveName(k int) string
+}
+
+// MoveStats describes the proposals made by one operator of a Mover.
+type MoveStats struct {
+	Name     string // name of the operator, if the State implements MoveNamer
+	Proposed int    // number of proposals made with the operator, including any discarded by Schedule.Admit
+	Accepted int    // number of those proposals adopted
+	Improved int    // number of those proposals of lower energy than the State from which they were proposed
+	Best     int    // number of those proposals that became the best State
+}
+
+// AcceptRate returns the fraction of the operator's proposals that were adopted, or 0 if it made none.
+func (st MoveStats) AcceptRate() float64 {
+	if st.Proposed == 0 {
+		return 0
+	}
+	return float64(st.Accepted) / float64(st.Proposed)
+}
+
+// A Policy chooses the operator for each proposal from a Mover and learns from the outcomes.
+// Anneal calls its methods on the goroutine that called Anneal; when Schedule.Workers is greater than 1,
+// it chooses the operators for a batch of proposals before any of their Outcomes is known.
+type Policy interface {
+	// Choose returns the operator with which to propose a neighbor of c.State.
+	Choose(c MoveContext) int
+
+	// Reward reports the outcome of a proposal made with an operator returned by Choose.
+	// Proposals discarded unconsidered from a batch are not reported.
+	Reward(o Outcome)
+}
+
+// A MoveContext describes the situation in which a Policy chooses an operator.
+// The Policy may derive features for its decision from the State.
+type MoveContext struct {
+	Run         int     // index of the most recent run, counting restarts
+	Temperature float64 // current temperature
+	State       State   // State from which the neighbor will be proposed
+	Energy      float64 // energy of State
+	Best        float64 // energy of the best State encountered so far
+	Moves       int     // number of operators
+}
+
+// An Outcome describes the fate of a proposal.
+type Outcome struct {
+	Move     int     // operator that produced the proposal
+	Delta    float64 // energy of the proposal minus that of the State from which it was proposed
+	Accepted bool    // whether the proposal was adopted
+	Best     bool    // whether the proposal became the best State
+}
+
+// A Bandit is a Policy that treats operators as the arms of a multi-armed bandit
+// and chooses among them by the UCB1 rule, using recency-weighted mean rewards so that it can follow
+// the changing usefulness of operators as the temperature falls.
+// An Outcome earns a reward of 1 for a new best State, 1/2 for another improvement, 1/4 for an accepted
+// deterioration, and 0 for a rejection.
+// It is the Policy that Anneal uses for a Mover when Schedule.Policy is nil.
+type Bandit struct {
+	C     float64 // exploration coefficient; zero means 1/2
+	Decay float64 // weight of the newest reward in the running means, in (0, 1]; zero means 0.01
+
+	n     []float64 // recency-weighted number of plays of each operator
+	mean  []float64 // recency-weighted mean reward of each operator
+	total float64
+}
+
+// NewBandit returns a Bandit with default parameters.
+func NewBandit() *Bandit { return &Bandit{} }
+
+// Choose returns the unplayed operator with the smallest index, if any,
+// and otherwise the operator with the largest upper confidence bound.
+func (b *Bandit) Choose(c MoveContext) int {
+	if len(b.n) != c.Moves {
+		b.n, b.mean, b.total = make([]float64, c.Moves), make([]float64, c.Moves), 0
+	}
+	C := b.C
+	if C == 0 {
+		C = 0.5
+	}
+	best, bound := 0, math.Inf(-1)
+	for k, n := range b.n {
+		if n == 0 {
+			return k
+		}
+		if u := b.mean[k] + C*math.Sqrt(math.Log(b.total)/n); u > bound {
+			best, bound = k, u
+		}
+	}
+	return best
+}
+
+// Reward updates the mean reward of o.Move.
+func (b *Bandit) Reward(o Outcome) {
+	if o.Move < 0 || o.Move >= len(b.n) {
+		return
+	}
+	var r float64
+	switch {
+	case o.Best:
+		r = 1
+	case o.Accepted && o.Delta < 0:
+		r = 0.5
+	case o.Accepted:
+		r = 0.25
+	}
+	d := b.Decay
+	if d == 0 {
+		d = 0.01
+	}
+	// Discount every count so that old plays gradually lose their weight.
+	b.total = 0
+	for k := range b.n {
+		b.n[k] *= 1 - d
+		b.total += b.n[k]
+	}
+	b.n[o.Move]++
+	b.total++
+	b.mean[o.Move] += (r - b.mean[o.Move]) / b.n[o.Move]
+}
+
+// choose returns the operator with which to propose a neighbor of s, whose energy is e, at temperature T,
+// or -1 if s is not a Mover.
+func (a *annealer) choose(s State, e, T float64) int {
+	m, ok := s.(Mover)
+	if !ok {
+		return -1
+	}
+	if a.policy == nil {
+		a.policy = NewBandit()
+	}
+	if a.moves == nil {
+		a.moves = make([]MoveStats, m.Moves())
+		if n, ok := m.(MoveNamer); ok {
+			for k := range a.moves {
+				a.moves[k].Name = n.MoveName(k)
+			}
+		}
+	}
+	return a.policy.Choose(MoveContext{
+		Run:         a.runs - 1,
+		Temperature: T,
+		State:       s,
+		Energy:      e,
+		Best:        a.ebest,
+		Moves:       m.Moves(),
+	})
+}
+
+// reward reports the outcome of the proposal p to the Policy, if one is in use, and records it in the statistics of its operator.
+func (a *annealer) reward(p proposal, dE float64, accepted bool) {
+	if a.policy == nil || p.move < 0 {
+		return
+	}
+	if p.move < len(a.moves) {
+		st := &a.moves[p.move]
+		st.Proposed++
+		if accepted {
+			st.Accepted++
+		}
+		if dE < 0 {
+			st.Improved++
+		}
+		if p.best {
+			st.Best++
+		}
+	}
+	a.policy.Reward(Outcome{Move: p.move, Delta: dE, Accepted: accepted, Best: p.best})
+}
@@ -0,0 +1,125 @@
+package anneal
+
+import "fmt"
+
+// A Coarsener is a State of a problem that can be approximated by a smaller, coarser problem,
+// for example by contracting the edges of a graph.
+type Coarsener interface {
+	State
+
+	// Coarsen returns a State of the coarser problem corresponding to the State,
+	// or nil if the problem cannot be coarsened further.
+	Coarsen() Refiner
+}
+
+// A Refiner is a State of a coarsened problem that can be projected onto the finer problem from which it came.
+// The neighbors of a Refiner must be Refiners of the same coarse problem.
+type Refiner interface {
+	State
+
+	// Refine returns the State of the finer problem that corresponds to the State.
+	Refine() State
+}
+
+// A Multilevel anneals a problem from coarse to fine: it coarsens the input State repeatedly,
+// anneals the coarsest problem, and then refines its solution one level at a time,
+// annealing again at each level from the projected solution.
+// Its Refine method instead begins from a coarse State and refines it as far as the States allow.
+type Multilevel struct {
+	Levels int // maximum number of coarsening steps, or of refinements by Refine, or 0 for as many as the States allow
+
+	// Ti, if positive, replaces Schedule.Ti at every level finer than the coarsest,
+	// so that a projected solution is improved rather than discarded by a hot restart.
+	Ti float64
+}
+
+// Run anneals s from coarse to fine with the Schedule configured by opts and returns a Result for each level annealed,
+// from coarsest to finest. The last Result describes the problem of s.
+func (m *Multilevel) Run(s State, opts ...Option) ([]Result, error) {
+	sch, fine, err := m.schedules(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// levels[0] is s; each following State is the coarsening of the one before.
+	levels := []State{s}
+	for m.Levels <= 0 || len(levels) <= m.Levels {
+		c, ok := levels[len(levels)-1].(Coarsener)
+		if !ok {
+			break
+		}
+		r := c.Coarsen()
+		if r == nil {
+			break
+		}
+		levels = append(levels, r)
+	}
+
+	var results []Result
+	cur := levels[len(levels)-1]
+	for l := len(levels) - 1; l >= 0; l-- {
+		level := sch
+		if l < len(levels)-1 {
+			level = fine
+		}
+		r, err := Run(cur, level)
+		results = append(results, r)
+		if err != nil {
+			return results, fmt.Errorf("anneal: level %d: %w", l, err)
+		}
+		if l > 0 {
+			ref, ok := r.Best.(Refiner)
+			if !ok {
+				return results, fmt.Errorf("anneal: level %d: best State %T does not implement Refiner", l, r.Best)
+			}
+			cur = ref.Refine()
+		}
+	}
+	return results, nil
+}
+
+// Refine anneals s, a State of a coarse representation of a problem, and then refines the solution repeatedly,
+// annealing again at each level from the projected solution, until the best State is not a Refiner
+// or Levels refinements have been made. It suits problems such as placement and layout,
+// whose representation can be made finer at will, for example by subdividing a grid,
+// but whose fine States have no natural coarsening. It returns a Result for each level annealed,
+// from coarsest to finest; level l is the problem reached by l refinements of s.
+func (m *Multilevel) Refine(s State, opts ...Option) ([]Result, error) {
+	sch, fine, err := m.schedules(opts)
+	if err != nil {
+		return nil, err
+	}
+	var results []Result
+	cur, level := s, sch
+	for l := 0; ; l++ {
+		r, err := Run(cur, level)
+		results = append(results, r)
+		if err != nil {
+			return results, fmt.Errorf("anneal: level %d: %w", l, err)
+		}
+		ref, ok := r.Best.(Refiner)
+		if !ok || m.Levels > 0 && l == m.Levels {
+			return results, nil
+		}
+		if cur = ref.Refine(); cur == nil {
+			return results, fmt.Errorf("anneal: level %d: Refine returned nil", l)
+		}
+		level = fine
+	}
+}
+
+// schedules returns the Schedule configured by opts for the first level annealed and that for the others.
+func (m *Multilevel) schedules(opts []Option) (first, rest *Schedule, err error) {
+	sch := configure(opts)
+	fine := *sch
+	if m.Ti > 0 {
+		fine.Ti = m.Ti
+	}
+	if err := sch.Validate(); err != nil {
+		return nil, nil, err
+	}
+	if err := fine.Validate(); err != nil {
+		return nil, nil, err
+	}
+	return sch, &fine, nil
+}
@@ -0,0 +1,19 @@
+package anneal
+
+import "math"
+
+// samples returns the number of energy evaluations to average per proposal at temperature T.
+// It grows from Schedule.Samples to Schedule.MaxSamples in proportion to the progress of the cooling,
+// measured as the fraction of the way from Ti to Tf on a logarithmic scale.
+func (a *annealer) samples(T float64) int {
+	lo, hi := a.nsample[0], a.nsample[1]
+	if hi == lo {
+		return lo
+	}
+	p := 1.0
+	if T > 0 {
+		p = math.Log(a.T0/T) / a.logRatio
+	}
+	p = min(max(p, 0), 1)
+	return lo + int(math.Round(p*float64(hi-lo)))
+}
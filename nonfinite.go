@@ -0,0 +1,66 @@
+package anneal
+
+import (
+	"fmt"
+	"math"
+)
+
+// A NonFinite is a policy for energies that are NaN or infinite.
+type NonFinite int
+
+const (
+	// RejectNonFinite rejects proposals whose energies are NaN or infinite.
+	// They consume iterations but are never adopted and never become the best State.
+	RejectNonFinite NonFinite = iota
+
+	// AbortNonFinite ends the search with a *NonFiniteError at the first NaN or infinite energy.
+	AbortNonFinite
+
+	// InfeasibleInf treats an energy of +Inf as marking an infeasible State, worse than every feasible one,
+	// and rejects proposals whose energies are NaN or -Inf.
+	// Moves between infeasible States are neutral, so that the search can wander until it finds a feasible one.
+	// The input State may be infeasible, in which case temperatures are not scaled by its energy.
+	InfeasibleInf
+)
+
+// A NonFiniteError reports an energy that is NaN or infinite.
+type NonFiniteError struct {
+	Energy float64
+}
+
+func (e *NonFiniteError) Error() string { return fmt.Sprintf("non-finite energy %v", e.Energy) }
+
+// admit applies the NonFinite policy to the energy e and reports whether a State of that energy may be considered.
+func (a *annealer) admit(e float64) (bool, error) {
+	switch {
+	case finite(e):
+		return true, nil
+	case a.nonFinite == AbortNonFinite:
+		return false, &NonFiniteError{e}
+	case a.nonFinite == InfeasibleInf:
+		return math.IsInf(e, 1), nil
+	}
+	return false, nil
+}
+
+// admitInput reports whether a starting State of energy e is acceptable under the NonFinite policy p.
+func admitInput(e float64, p NonFinite) bool {
+	return finite(e) || p == InfeasibleInf && math.IsInf(e, 1)
+}
+
+// delta returns the difference f - g of energies, which is zero if both are infeasible.
+func delta(f, g float64) float64 {
+	if math.IsInf(f, 1) && math.IsInf(g, 1) {
+		return 0
+	}
+	return f - g
+}
+
+// energyScale returns the scale of temperatures for an input State of energy e:
+// its magnitude, or 1 if the State is infeasible.
+func energyScale(e float64) float64 {
+	if math.IsInf(e, 0) {
+		return 1
+	}
+	return math.Abs(e)
+}
@@ -0,0 +1,83 @@
+package anneal
+
+import (
+	"math"
+	"time"
+)
+
+// Progress describes the state of an annealing run at a particular iteration.
+type Progress struct {
+	Run         int     // index of the run, counting restarts
+	Iter        int     // number of iterations completed in the run
+	Temperature float64 // temperature at iteration Iter
+	Energy      float64 // energy of the current State
+	Best        float64 // energy of the best State encountered in any run so far
+	Accepted    int     // number of proposals adopted in the run so far
+
+	Elapsed   time.Duration // time elapsed in the run
+	Remaining time.Duration // estimated time remaining in the run, or 0 if unknown
+
+	Labels map[string]string // labels identifying the search; see Schedule.Labels. It must not be modified.
+}
+
+// An Observer receives reports of the progress of annealing runs.
+// Anneal calls Observe every Schedule.Every iterations and at the end of each run,
+// on the goroutine that called Anneal.
+type Observer interface {
+	Observe(p Progress)
+}
+
+// An ObserverFunc is a function that implements Observer.
+type ObserverFunc func(p Progress)
+
+func (f ObserverFunc) Observe(p Progress) { f(p) }
+
+// minOverheadWindow is the time a run must have lasted before the overhead of reporting is judged.
+const minOverheadWindow = 10 * time.Millisecond
+
+// report sends a Progress report to the Observer.
+// If the overhead of reporting is limited, it adjusts the reporting interval.
+func (a *annealer) report(i int, T, e float64, accepted int) {
+	var t0 time.Time
+	if a.overhead > 0 {
+		t0 = a.clock.Now()
+	}
+	a.obs.Observe(a.progress(i, T, e, accepted))
+	if a.overhead > 0 {
+		now := a.clock.Now()
+		a.obsTime += now.Sub(t0)
+		elapsed := now.Sub(a.start)
+		if elapsed < minOverheadWindow {
+			return
+		}
+		switch frac := float64(a.obsTime) / float64(elapsed); {
+		case frac > a.overhead && a.every < math.MaxInt/2:
+			a.every *= 2
+		case frac < a.overhead/4 && a.every > a.minEvery:
+			a.every = max(a.every/2, a.minEvery)
+		}
+	}
+}
+
+// progress returns a Progress report for iteration i of the current run at temperature T,
+// where the current energy is e and accepted proposals have been adopted.
+// The remaining time of a run measured in iterations is extrapolated from the rate so far.
+func (a *annealer) progress(i int, T, e float64, accepted int) Progress {
+	p := Progress{
+		Run:         a.runs - 1,
+		Iter:        i,
+		Temperature: T,
+		Energy:      e,
+		Best:        a.ebest,
+		Accepted:    accepted,
+		Elapsed:     a.elapsed(),
+		Labels:      a.labels,
+	}
+	switch {
+	case a.duration > 0:
+		p.Remaining = max(a.duration-p.Elapsed, 0)
+	case i > 0:
+		p.Remaining = time.Duration(float64(p.Elapsed) * float64(max(a.iter-i, 0)) / float64(i))
+	}
+	return p
+}
@@ -0,0 +1,149 @@
+/*
+Package optimize adapts package anneal to the minimization of functions of real vectors
+in the shape of gonum.org/v1/gonum/optimize, so that programs built around that package
+can use simulated annealing for global optimization with little change. It does not depend on gonum:
+its Problem, Settings, and Result mirror the fields of gonum's types of the same names that apply
+to a derivative-free global method, and a gonum Problem converts by copying its Func:
+
+	res, err := optimize.Minimize(optimize.Problem{Func: p.Func}, x0, nil)
+	if err != nil {
+		return err
+	}
+	fmt.Println(res.X, res.F, res.Status)
+
+Minimize anneals an anneal.VectorState whose steps are scaled to adopt about 44% of proposals,
+the rate that is efficient for moves of one coordinate at a time, and finishes with a greedy descent.
+*/
+package optimize
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dkmccandless/anneal"
+)
+
+// A Problem describes a function to minimize.
+type Problem struct {
+	Func func(x []float64) float64 // objective function; must not retain or modify x
+
+	// Lower and Upper, if not nil, bound each coordinate of the search. They must have the length of the initial location.
+	Lower, Upper []float64
+}
+
+// Settings control a minimization. A nil *Settings means the zero Settings.
+type Settings struct {
+	// FuncEvaluations, if positive, is the number of evaluations of Func to spend,
+	// and Runtime, if positive, the time to spend in each run of the annealing schedule.
+	// If both are zero, the search performs anneal.NewSchedule().Iter iterations.
+	FuncEvaluations int
+	Runtime         time.Duration
+
+	Step float64 // initial size of the steps of the search, or 0 for 1; it adapts as the search proceeds
+
+	// Options further configure the annealing schedule, overriding those that Minimize derives from the Settings.
+	Options []anneal.Option
+}
+
+// A Location is a point of the search and the value of the objective function there.
+type Location struct {
+	X []float64
+	F float64
+}
+
+// Stats describe the effort of a minimization.
+type Stats struct {
+	FuncEvaluations int           // number of evaluations of Func
+	Runtime         time.Duration // time elapsed
+}
+
+// A Status describes why a minimization ended.
+type Status int
+
+const (
+	NotTerminated           Status = iota
+	Success                        // the annealing schedule is complete
+	FunctionEvaluationLimit        // Settings.FuncEvaluations were spent
+	RuntimeLimit                   // Settings.Runtime elapsed for each run of the schedule
+	Failure                        // the search stopped with an error
+)
+
+var statusNames = [...]string{
+	NotTerminated:           "NotTerminated",
+	Success:                 "Success",
+	FunctionEvaluationLimit: "FunctionEvaluationLimit",
+	RuntimeLimit:            "RuntimeLimit",
+	Failure:                 "Failure",
+}
+
+func (s Status) String() string {
+	if s >= 0 && int(s) < len(statusNames) {
+		return statusNames[s]
+	}
+	return fmt.Sprintf("Status(%d)", int(s))
+}
+
+// A Result is the outcome of a minimization: the best location found, the effort spent, and the reason it ended.
+type Result struct {
+	Location
+	Stats
+	Status Status
+}
+
+// acceptTarget is the fraction of proposals toward which Minimize steers the size of its steps.
+const acceptTarget = 0.44
+
+// polish is the number of consecutive failures to improve that end the finishing descent.
+const polish = 1000
+
+// Minimize searches for the minimum of p.Func starting from initX, which it does not modify.
+// If the search stops with an error, Minimize returns it together with the Result of the search until then.
+func Minimize(p Problem, initX []float64, settings *Settings) (*Result, error) {
+	switch {
+	case p.Func == nil:
+		return nil, errors.New("optimize: Problem has no Func")
+	case len(initX) == 0:
+		return nil, errors.New("optimize: initial location is empty")
+	case p.Lower != nil && len(p.Lower) != len(initX), p.Upper != nil && len(p.Upper) != len(initX):
+		return nil, errors.New("optimize: bounds and initial location have different lengths")
+	}
+	if settings == nil {
+		settings = new(Settings)
+	}
+	step := settings.Step
+	if step == 0 {
+		step = 1
+	}
+	vp := &anneal.VectorProblem{Func: p.Func, Lower: p.Lower, Upper: p.Upper, Step: step}
+	opts := []anneal.Option{anneal.WithAcceptTarget(acceptTarget, 0), anneal.WithPolish(polish)}
+	if settings.FuncEvaluations > 0 {
+		// Let the budget end the search, with the schedule sized to spend about as many iterations.
+		opts = append(opts, anneal.WithIterations(settings.FuncEvaluations), anneal.WithEvals(settings.FuncEvaluations))
+	}
+	if settings.Runtime > 0 {
+		opts = append(opts, anneal.WithDuration(settings.Runtime))
+	}
+	opts = append(opts, settings.Options...)
+
+	start := time.Now()
+	r, err := anneal.Run(vp.NewState(append([]float64(nil), initX...)), opts...)
+	if r.Best == nil && err != nil {
+		return nil, err
+	}
+	res := &Result{
+		Location: Location{X: r.Best.(*anneal.VectorState).X, F: r.Energy},
+		Stats:    Stats{FuncEvaluations: r.Evaluations, Runtime: time.Since(start)},
+	}
+	switch {
+	case err != nil:
+		res.Status = Failure
+	case settings.FuncEvaluations > 0 && r.Evaluations >= settings.FuncEvaluations:
+		res.Status = FunctionEvaluationLimit
+	case settings.Runtime > 0:
+		res.Status = RuntimeLimit
+	default:
+		res.Status = Success
+	}
+	return res, err
+}
@@ -0,0 +1,229 @@
+package anneal
+
+import (
+	"io"
+	"maps"
+	"math/rand"
+	"time"
+)
+
+// An Option configures an annealing run.
+type Option interface {
+	apply(sch *Schedule)
+}
+
+// apply replaces the configuration with a copy of sch. A nil *Schedule leaves it unchanged.
+func (sch *Schedule) apply(c *Schedule) {
+	if sch != nil {
+		*c = *sch
+	}
+}
+
+// An optionFunc is an Option that modifies the configuration.
+type optionFunc func(sch *Schedule)
+
+func (f optionFunc) apply(sch *Schedule) { f(sch) }
+
+// configure returns a new Schedule with the default values and opts applied in order.
+func configure(opts []Option) *Schedule {
+	sch := NewSchedule()
+	for _, o := range opts {
+		if o != nil {
+			o.apply(sch)
+		}
+	}
+	return sch
+}
+
+// WithIterations sets the number of iterations in each run.
+func WithIterations(n int) Option {
+	return optionFunc(func(sch *Schedule) { sch.Iter = n })
+}
+
+// WithTemperatures sets the initial and final temperatures, as multiples of the input State's energy.
+func WithTemperatures(ti, tf float64) Option {
+	return optionFunc(func(sch *Schedule) { sch.Ti, sch.Tf = ti, tf })
+}
+
+// WithDuration sets the wall-clock length of each run; see Schedule.Duration.
+func WithDuration(d time.Duration) Option {
+	return optionFunc(func(sch *Schedule) { sch.Duration = d })
+}
+
+// WithCooling sets the law by which the temperature decays and its rate; see Cooling.
+func WithCooling(c Cooling, rate float64) Option {
+	return optionFunc(func(sch *Schedule) { sch.Cooling, sch.CoolRate = c, rate })
+}
+
+// WithBlock sets the number of iterations at each temperature level; see Schedule.Block.
+func WithBlock(n int) Option {
+	return optionFunc(func(sch *Schedule) { sch.Block = n })
+}
+
+// WithRestarts sets the number of additional runs and the weight of the diversification penalty; see Schedule.Restarts.
+func WithRestarts(n int, diversify float64) Option {
+	return optionFunc(func(sch *Schedule) { sch.Restarts, sch.Diversify = n, diversify })
+}
+
+// WithShared shares the best State with cooperating searches through sh,
+// exchanging it every exchange iterations as well as at restarts; see Schedule.Shared.
+func WithShared(sh *Shared, exchange int) Option {
+	return optionFunc(func(sch *Schedule) { sch.Shared, sch.Exchange = sh, exchange })
+}
+
+// WithInitializers sets the Initializers that construct the starting States of restarts.
+func WithInitializers(in ...Initializer) Option {
+	return optionFunc(func(sch *Schedule) { sch.Initializers = in })
+}
+
+// WithPacing limits the search to rate evaluations per second and yields the processor every yield evaluations;
+// see Schedule.Rate.
+func WithPacing(rate float64, yield int) Option {
+	return optionFunc(func(sch *Schedule) { sch.Rate, sch.Yield = rate, yield })
+}
+
+// WithWorkers sets the number of goroutines evaluating neighbors concurrently.
+func WithWorkers(n int) Option {
+	return optionFunc(func(sch *Schedule) { sch.Workers = n })
+}
+
+// WithScan sets the temperature below which to scan neighborhoods systematically; see Schedule.Scan.
+func WithScan(T float64) Option {
+	return optionFunc(func(sch *Schedule) { sch.Scan = T })
+}
+
+// WithAudit sets the interval in iterations between energy audits; see Schedule.Audit.
+func WithAudit(n int) Option {
+	return optionFunc(func(sch *Schedule) { sch.Audit = n })
+}
+
+// WithAcceptTarget sets the fraction of proposals to adopt toward which to steer the size of the proposals of Scalers,
+// and the interval in iterations between adjustments; see Scaler.
+func WithAcceptTarget(target float64, window int) Option {
+	return optionFunc(func(sch *Schedule) { sch.AcceptTarget, sch.ScaleWindow = target, window })
+}
+
+// WithRecent sets the number of recently seen States to remember in order to detect duplicate proposals.
+func WithRecent(n int) Option {
+	return optionFunc(func(sch *Schedule) { sch.Recent = n })
+}
+
+// WithSamples sets the numbers of energy evaluations averaged per proposal at the start and end of each run.
+func WithSamples(samples, maxSamples int) Option {
+	return optionFunc(func(sch *Schedule) { sch.Samples, sch.MaxSamples = samples, maxSamples })
+}
+
+// WithEvals limits the total number of energy evaluations.
+func WithEvals(n int) Option {
+	return optionFunc(func(sch *Schedule) { sch.Evals = n })
+}
+
+// WithTarget stops the search as soon as it finds a State of energy e or less.
+func WithTarget(e float64) Option {
+	return optionFunc(func(sch *Schedule) { sch.Target, sch.UseTarget = e, true })
+}
+
+// WithLandscape sets the destination of a log of every State evaluated.
+func WithLandscape(w io.Writer) Option {
+	return optionFunc(func(sch *Schedule) { sch.Landscape = w })
+}
+
+// WithDecisions sets the writer that receives a log of the random decisions of the search; see Replay.
+func WithDecisions(w io.Writer) Option {
+	return optionFunc(func(sch *Schedule) { sch.Decisions = w })
+}
+
+// WithLocalSearch hybridizes the search with ls, applied every every iterations, or from each new best State
+// if every is zero, with the given budget; see Schedule.LocalSearch.
+func WithLocalSearch(ls LocalSearch, every, budget int) Option {
+	return optionFunc(func(sch *Schedule) { sch.LocalSearch, sch.LocalEvery, sch.LocalBudget = ls, every, budget })
+}
+
+// WithHistory records the last n States adopted in Result.History; see Schedule.History.
+func WithHistory(n int) Option {
+	return optionFunc(func(sch *Schedule) { sch.History = n })
+}
+
+// WithPolish adds a greedy finishing descent that stops after n consecutive proposals fail to improve.
+func WithPolish(n int) Option {
+	return optionFunc(func(sch *Schedule) { sch.Polish = n })
+}
+
+// WithKeep sets the number of best distinct States to retain, and the equality of States that do not implement Hasher.
+func WithKeep(k int, equal func(a, b State) bool) Option {
+	return optionFunc(func(sch *Schedule) { sch.Keep, sch.Equal = k, equal })
+}
+
+// WithObserver sets the recipient of progress reports and the interval in iterations between them.
+func WithObserver(o Observer, every int) Option {
+	return optionFunc(func(sch *Schedule) { sch.Observer, sch.Every = o, every })
+}
+
+// WithMaxOverhead limits the fraction of time spent reporting progress; see Schedule.MaxOverhead.
+func WithMaxOverhead(f float64) Option {
+	return optionFunc(func(sch *Schedule) { sch.MaxOverhead = f })
+}
+
+// WithPlateau sets the tolerance below which energy differences are treated as zero
+// and the probability of adopting a proposal within it; see Schedule.Plateau.
+func WithPlateau(eps, accept float64) Option {
+	return optionFunc(func(sch *Schedule) { sch.Plateau, sch.PlateauAccept = eps, accept })
+}
+
+// WithBetter sets the comparison that determines the best State.
+func WithBetter(better func(s State, e float64, best State, ebest float64) bool) Option {
+	return optionFunc(func(sch *Schedule) { sch.Better = better })
+}
+
+// WithAcceptProb sets the probability of adopting a State whose energy exceeds the current energy by dE at temperature T.
+func WithAcceptProb(p func(dE, T float64) float64) Option {
+	return optionFunc(func(sch *Schedule) { sch.AcceptProb = p })
+}
+
+// WithNonFinite sets the policy for energies that are NaN or infinite.
+func WithNonFinite(p NonFinite) Option {
+	return optionFunc(func(sch *Schedule) { sch.NonFinite = p })
+}
+
+// WithPolicy sets the Policy that chooses operators for States that implement Mover.
+func WithPolicy(p Policy) Option {
+	return optionFunc(func(sch *Schedule) { sch.Policy = p })
+}
+
+// WithAcceptor sets the oracle that decides whether to adopt each proposal.
+func WithAcceptor(acc Acceptor) Option {
+	return optionFunc(func(sch *Schedule) { sch.Acceptor = acc })
+}
+
+// WithLabels adds the given key/value pairs to the labels identifying the search,
+// replacing the values of keys already present.
+func WithLabels(labels map[string]string) Option {
+	return optionFunc(func(sch *Schedule) {
+		l := maps.Clone(sch.Labels)
+		if l == nil {
+			l = make(map[string]string, len(labels))
+		}
+		maps.Copy(l, labels)
+		sch.Labels = l
+	})
+}
+
+// WithTracer sets the recipient of a span for the search.
+func WithTracer(t Tracer) Option {
+	return optionFunc(func(sch *Schedule) { sch.Tracer = t })
+}
+
+// WithClock sets the source of the current time.
+func WithClock(c Clock) Option {
+	return optionFunc(func(sch *Schedule) { sch.Clock = c })
+}
+
+// WithSeed makes the search reproducible by drawing its randomness from sources seeded by seed; see Schedule.Seed.
+func WithSeed(seed int64) Option {
+	return optionFunc(func(sch *Schedule) { sch.Seed, sch.UseSeed = seed, true })
+}
+
+// WithRand sets the source of randomness for acceptance decisions.
+func WithRand(r *rand.Rand) Option {
+	return optionFunc(func(sch *Schedule) { sch.Rand = r })
+}
@@ -0,0 +1,77 @@
+package anneal
+
+import (
+	"maps"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestOptions(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		name string
+		opt  Option
+		ok   func(sch *Schedule) bool
+	}{
+		{"WithIterations", WithIterations(123), func(sch *Schedule) bool { return sch.Iter == 123 }},
+		{"WithTemperatures", WithTemperatures(5, 0.5), func(sch *Schedule) bool { return sch.Ti == 5 && sch.Tf == 0.5 }},
+		{"WithDuration", WithDuration(time.Minute), func(sch *Schedule) bool { return sch.Duration == time.Minute }},
+		{"WithCooling", WithCooling(CoolGeometric, 0.9), func(sch *Schedule) bool { return sch.Cooling == CoolGeometric && sch.CoolRate == 0.9 }},
+		{"WithRestarts", WithRestarts(4, 0.5), func(sch *Schedule) bool { return sch.Restarts == 4 && sch.Diversify == 0.5 }},
+		{"WithWorkers", WithWorkers(3), func(sch *Schedule) bool { return sch.Workers == 3 }},
+		{"WithSamples", WithSamples(2, 8), func(sch *Schedule) bool { return sch.Samples == 2 && sch.MaxSamples == 8 }},
+		{"WithTarget", WithTarget(-1), func(sch *Schedule) bool { return sch.Target == -1 && sch.UseTarget }},
+		{"WithTarget zero", WithTarget(0), func(sch *Schedule) bool { return sch.Target == 0 && sch.UseTarget }},
+		{"WithObserver", WithObserver(nil, 50), func(sch *Schedule) bool { return sch.Every == 50 }},
+		{"WithPlateau", WithPlateau(1e-3, 0.5), func(sch *Schedule) bool { return sch.Plateau == 1e-3 && sch.PlateauAccept == 0.5 }},
+		{"WithNonFinite", WithNonFinite(AbortNonFinite), func(sch *Schedule) bool { return sch.NonFinite == AbortNonFinite }},
+		{"WithSeed", WithSeed(9), func(sch *Schedule) bool { return sch.Seed == 9 && sch.UseSeed }},
+		{"WithRand", WithRand(r), func(sch *Schedule) bool { return sch.Rand == r }},
+		{"nil", nil, func(sch *Schedule) bool { return sch.Iter == NewSchedule().Iter }},
+		{"nil *Schedule", (*Schedule)(nil), func(sch *Schedule) bool { return sch.Iter == NewSchedule().Iter }},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if sch := configure([]Option{test.opt}); !test.ok(sch) {
+				t.Errorf("configured Schedule %+v", *sch)
+			}
+		})
+	}
+}
+
+func TestOptionsOrder(t *testing.T) {
+	base := NewSchedule()
+	base.Iter, base.Restarts = 500, 2
+	base.Labels = map[string]string{"a": "1"}
+
+	// Options apply in order, and a *Schedule replaces every field.
+	sch := configure([]Option{WithWorkers(4), base, WithIterations(700), WithLabels(map[string]string{"b": "2"}), WithIterations(800)})
+	if sch.Iter != 800 || sch.Restarts != 2 || sch.Workers != 0 {
+		t.Errorf("Iter, Restarts, Workers = %d, %d, %d, want 800, 2, 0", sch.Iter, sch.Restarts, sch.Workers)
+	}
+	if want := map[string]string{"a": "1", "b": "2"}; !maps.Equal(sch.Labels, want) {
+		t.Errorf("Labels = %v, want %v", sch.Labels, want)
+	}
+	// Neither the *Schedule nor its Labels is modified by the options that follow it.
+	if base.Iter != 500 || len(base.Labels) != 1 {
+		t.Errorf("options modified the Schedule option: Iter = %d, Labels = %v", base.Iter, base.Labels)
+	}
+
+	// Without options, the defaults of NewSchedule apply.
+	if sch, def := configure(nil), NewSchedule(); sch.Iter != def.Iter || sch.Ti != def.Ti || sch.Tf != def.Tf || sch.Every != def.Every {
+		t.Errorf("configure(nil) = %+v, want the defaults %+v", *sch, *def)
+	}
+}
+
+func TestAnnealSchedule(t *testing.T) {
+	// A *Schedule configures Anneal as the options it stands for do.
+	sch := NewSchedule()
+	sch.Iter = 1000
+	best, err := Anneal(line(20), sch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if best != line(0) {
+		t.Errorf("Anneal(line(20), sch) = %v, want 0", best)
+	}
+}
@@ -0,0 +1,30 @@
+package anneal
+
+import (
+	"runtime"
+	"time"
+)
+
+// paceSlices is the number of times per second that a paced search compares its progress with the clock,
+// so that it sleeps for periods long enough to be measured reliably.
+const paceSlices = 100
+
+// pace sleeps if the search has performed more evaluations than Schedule.Rate allows for the time elapsed,
+// and yields the processor every Schedule.Yield evaluations.
+func (a *annealer) pace() {
+	if a.yield > 0 && a.evals >= a.nextYld {
+		runtime.Gosched()
+		a.nextYld = a.evals + a.yield
+	}
+	if a.rate == 0 || a.evals < a.nextPace {
+		return
+	}
+	if a.pace0.IsZero() {
+		a.pace0 = time.Now()
+	}
+	due := a.pace0.Add(time.Duration(float64(a.evals) / a.rate * float64(time.Second)))
+	if d := time.Until(due); d > 0 {
+		time.Sleep(d)
+	}
+	a.nextPace = a.evals + max(int(a.rate/paceSlices), 1)
+}
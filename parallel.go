@@ -0,0 +1,52 @@
+package anneal
+
+import "sync"
+
+// A pool is a fixed set of goroutines that propose and evaluate neighbors.
+type pool struct {
+	req  chan request
+	wg   sync.WaitGroup
+	done sync.WaitGroup // requests of the current batch, kept here so that a batch does not allocate
+}
+
+// A request asks a worker to store a neighbor of s at temperature T, proposed by the operator move,
+// and its energy averaged over r evaluations in out.
+type request struct {
+	s    State
+	T    float64
+	move int
+	r    int
+	out  *proposal
+	done *sync.WaitGroup
+}
+
+func newPool(n int) *pool {
+	p := &pool{req: make(chan request)}
+	p.wg.Add(n)
+	for range n {
+		go func() {
+			defer p.wg.Done()
+			for r := range p.req {
+				*r.out = evaluate(r.s, r.T, r.move, r.r)
+				r.done.Done()
+			}
+		}()
+	}
+	return p
+}
+
+// propose fills batch with neighbors of s at temperature T and their energies averaged over r evaluations,
+// evaluated concurrently, each proposed by the operator already stored in its move field.
+func (p *pool) propose(s State, T float64, r int, batch []proposal) {
+	p.done.Add(len(batch))
+	for i := range batch {
+		p.req <- request{s, T, batch[i].move, r, &batch[i], &p.done}
+	}
+	p.done.Wait()
+}
+
+// close stops the workers and waits for them to exit.
+func (p *pool) close() {
+	close(p.req)
+	p.wg.Wait()
+}
@@ -0,0 +1,145 @@
+package anneal
+
+import (
+	"iter"
+	"math/rand"
+	"slices"
+	"sync"
+)
+
+// A PermMove is a set of kinds of moves on a permutation.
+type PermMove int
+
+const (
+	PermSwap    PermMove = 1 << iota // exchange two elements
+	PermInsert                       // remove one element and reinsert it at another position
+	PermReverse                      // reverse a contiguous segment; on a tour, this is a 2-opt move
+
+	PermAll = PermSwap | PermInsert | PermReverse
+)
+
+// A PermutationProblem describes the minimization of a cost function of a permutation,
+// such as the length of a tour or the makespan of a job sequence.
+type PermutationProblem struct {
+	Cost  func(perm []int) float64 // cost function; must not retain or modify perm
+	Moves PermMove                 // kinds of moves to choose among uniformly; zero means PermAll
+}
+
+// NewState returns a PermutationState of p holding perm. It does not copy perm.
+func (p *PermutationProblem) NewState(perm []int) *PermutationState {
+	return &PermutationState{Perm: perm, p: p}
+}
+
+// A PermutationState is an ordering of elements in the search space of a PermutationProblem.
+// Its energy is the value of the cost function.
+// Each neighbor differs from it by a single move chosen uniformly from the kinds the problem allows.
+// PermutationState implements Enumerator, Seeded, and Recycler by drawing the storage of its neighbors from a pool.
+type PermutationState struct {
+	Perm []int
+	p    *PermutationProblem
+	rand source
+}
+
+// Energy returns the cost of s.Perm.
+func (s *PermutationState) Energy() float64 { return s.p.Cost(s.Perm) }
+
+// Neighbor returns a State that differs from s by a randomly chosen move.
+func (s *PermutationState) Neighbor() State {
+	n := len(s.Perm)
+	t := s.CloneInto(permStates.Get().(*PermutationState))
+	if n < 2 {
+		return t
+	}
+	kinds := s.p.kinds()
+	m := kinds[s.rand.Intn(len(kinds))]
+	i, j := s.rand.Intn(n), s.rand.Intn(n-1)
+	if j >= i {
+		j++
+	}
+	if m != PermInsert && i > j {
+		i, j = j, i
+	}
+	permute(t.Perm, m, i, j)
+	return t
+}
+
+// UseRand sets the source of randomness of s and its neighbors.
+func (s *PermutationState) UseRand(r *rand.Rand) { s.rand.r = r }
+
+// Release returns the storage of s to the pool from which Neighbor draws.
+func (s *PermutationState) Release() { permStates.Put(s) }
+
+// permStates holds released PermutationStates for reuse.
+var permStates = sync.Pool{New: func() any { return new(PermutationState) }}
+
+// Neighborhood returns an iterator over every State that differs from s by a single allowed move.
+func (s *PermutationState) Neighborhood() iter.Seq[State] {
+	return func(yield func(State) bool) {
+		n := len(s.Perm)
+		for _, m := range s.p.kinds() {
+			for i := 0; i < n; i++ {
+				for j := 0; j < n; j++ {
+					if i == j || m != PermInsert && i > j {
+						continue
+					}
+					t := s.CloneInto(nil)
+					permute(t.Perm, m, i, j)
+					if !yield(t) {
+						return
+					}
+				}
+			}
+		}
+	}
+}
+
+// CloneInto copies s into dst, reusing the storage of dst.Perm if it is large enough, and returns dst.
+// If dst is nil, it allocates a new PermutationState. Neighbor uses it to fill released States;
+// programs that manage their own pools of States can use it likewise.
+func (s *PermutationState) CloneInto(dst *PermutationState) *PermutationState {
+	if dst == nil {
+		dst = new(PermutationState)
+	}
+	dst.Perm = append(dst.Perm[:0], s.Perm...)
+	dst.p, dst.rand = s.p, s.rand
+	return dst
+}
+
+// kinds returns the individual kinds of moves that p allows.
+func (p *PermutationProblem) kinds() []PermMove {
+	if m := p.Moves & PermAll; m != 0 {
+		return permKinds[m]
+	}
+	return permKinds[PermAll]
+}
+
+// permKinds lists the individual kinds of moves in each set.
+var permKinds = func() (t [PermAll + 1][]PermMove) {
+	for moves := range t {
+		for _, m := range []PermMove{PermSwap, PermInsert, PermReverse} {
+			if PermMove(moves)&m != 0 {
+				t[moves] = append(t[moves], m)
+			}
+		}
+	}
+	return t
+}()
+
+// permute alters p in place by the move m on positions i and j.
+// For PermSwap and PermReverse, i < j. For PermInsert, the element at i moves to position j.
+func permute(p []int, m PermMove, i, j int) {
+	switch m {
+	case PermSwap:
+		p[i], p[j] = p[j], p[i]
+	case PermInsert:
+		x := p[i]
+		if i < j {
+			copy(p[i:j], p[i+1:j+1])
+		} else {
+			copy(p[j+1:i+1], p[j:i])
+		}
+		p[j] = x
+	case PermReverse:
+		slices.Reverse(p[i : j+1])
+	}
+}
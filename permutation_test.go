@@ -0,0 +1,111 @@
+package anneal
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func benchPermutation() *PermutationState {
+	p := &PermutationProblem{Cost: func(perm []int) float64 { return float64(perm[0]) }}
+	return p.NewState(rand.Perm(1000))
+}
+
+func BenchmarkPermutationNeighbor(b *testing.B) {
+	s := benchPermutation()
+	b.ReportAllocs()
+	for b.Loop() {
+		s.Neighbor().(Recycler).Release()
+	}
+}
+
+func BenchmarkPermutationCloneInto(b *testing.B) {
+	s := benchPermutation()
+	dst := s.CloneInto(nil)
+	b.ReportAllocs()
+	for b.Loop() {
+		s.CloneInto(dst)
+	}
+}
+
+// permMoved reports whether q differs from p by the move m on some positions.
+func permMoved(p, q []int, m PermMove) bool {
+	for i := range p {
+		for j := range p {
+			if i == j || m != PermInsert && i > j {
+				continue
+			}
+			r := slices.Clone(p)
+			permute(r, m, i, j)
+			if slices.Equal(r, q) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func TestPermutationNeighbor(t *testing.T) {
+	for _, moves := range []PermMove{PermSwap, PermInsert, PermReverse, PermSwap | PermReverse, PermAll, 0} {
+		for _, n := range []int{0, 1, 2, 3, 8} {
+			t.Run(fmt.Sprintf("Moves=%d/n=%d", moves, n), func(t *testing.T) {
+				p := &PermutationProblem{Cost: func(perm []int) float64 { return float64(perm[0]) }, Moves: moves}
+				s := p.NewState(rand.Perm(n))
+				orig := slices.Clone(s.Perm)
+				kinds := p.kinds()
+				for range 200 {
+					next := s.Neighbor().(*PermutationState)
+					if !slices.Equal(s.Perm, orig) {
+						t.Fatalf("Neighbor changed the receiver from %v to %v", orig, s.Perm)
+					}
+					if n > 0 && &next.Perm[0] == &s.Perm[0] {
+						t.Fatal("neighbor shares its permutation with the receiver")
+					}
+					if got := slices.Sorted(slices.Values(next.Perm)); !slices.Equal(got, slices.Sorted(slices.Values(orig))) {
+						t.Fatalf("neighbor %v of %v is not a permutation of the same elements", next.Perm, orig)
+					}
+					if n < 2 {
+						if !slices.Equal(next.Perm, orig) {
+							t.Fatalf("neighbor %v of %v differs", next.Perm, orig)
+						}
+					} else if !slices.ContainsFunc(kinds, func(m PermMove) bool { return permMoved(orig, next.Perm, m) }) {
+						t.Fatalf("neighbor %v of %v differs by no allowed move", next.Perm, orig)
+					}
+					next.Release()
+				}
+			})
+		}
+	}
+}
+
+func TestPermutationNeighborhood(t *testing.T) {
+	const n = 6
+	for _, test := range []struct {
+		moves PermMove
+		want  int // distinct neighbors
+	}{
+		{PermSwap, n * (n - 1) / 2},
+		{PermReverse, n * (n - 1) / 2},
+		// Inserting an element at the position of an adjacent one swaps them, and is counted once.
+		{PermInsert, n*(n-1) - (n - 1)},
+	} {
+		p := &PermutationProblem{Cost: func(perm []int) float64 { return 0 }, Moves: test.moves}
+		s := p.NewState(rand.Perm(n))
+		orig := slices.Clone(s.Perm)
+		seen := make(map[string]bool)
+		for next := range s.Neighborhood() {
+			perm := next.(*PermutationState).Perm
+			if !permMoved(orig, perm, test.moves) {
+				t.Errorf("Moves %d: neighbor %v of %v differs by no allowed move", test.moves, perm, orig)
+			}
+			seen[fmt.Sprint(perm)] = true
+		}
+		if !slices.Equal(s.Perm, orig) {
+			t.Errorf("Moves %d: Neighborhood changed the receiver from %v to %v", test.moves, orig, s.Perm)
+		}
+		if len(seen) != test.want {
+			t.Errorf("Moves %d: %d distinct neighbors, want %d", test.moves, len(seen), test.want)
+		}
+	}
+}
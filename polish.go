@@ -0,0 +1,66 @@
+package anneal
+
+import (
+	"fmt"
+	"math"
+)
+
+// A descent is the progress of the finishing descent.
+type descent struct {
+	s     State
+	e     float64
+	T     float64 // temperature at which to propose neighbors
+	n     int     // number of consecutive failures at which to stop
+	fails int
+	steps int // number of improvements adopted
+}
+
+// polish returns a greedy descent from the best State that samples neighbors at temperature T,
+// adopts the first with lower energy, and stops after n consecutive samples fail to improve
+// or the evaluation budget is spent.
+func (a *annealer) polish(n int, T float64) *descent {
+	return &descent{s: keep(a.best), e: a.ebest, T: T, n: n}
+}
+
+// descend advances the descent d by one batch of samples and reports whether it continues.
+func (a *annealer) descend(d *descent) (bool, error) {
+	if d.fails >= d.n || a.stopped() {
+		return false, nil
+	}
+	a.pace()
+	batch := a.propose(d.s, d.e, a.budget(d.n-d.fails), d.T)
+	for j, p := range batch {
+		if p.err != nil {
+			return false, fmt.Errorf("anneal: finishing descent: %w", p.err)
+		}
+		ok, err := a.admit(p.e)
+		if err != nil {
+			return false, fmt.Errorf("anneal: finishing descent: %w", err)
+		}
+		if !ok {
+			a.reward(p, math.NaN(), false)
+			a.recycle(p.s, false)
+			d.fails++
+			continue
+		}
+		p.best = a.consider(p.s, p.e)
+		improved := less(p.s, p.e, d.s, d.e)
+		a.reward(p, delta(p.e, d.e), improved)
+		a.log(d.s, p.s, p.e, LandscapeConsidered|landscapeFlag(improved))
+		if improved {
+			notify(d.s, p.s)
+			d.s, d.e = p.s, p.e
+			d.fails = 0
+			a.adopted(d.s, d.e, -1, d.steps)
+			d.steps++
+			// The rest of the batch is discarded unexamined.
+			for _, q := range batch[j+1:] {
+				a.recycle(q.s, false)
+			}
+			return true, nil
+		}
+		a.recycle(p.s, p.best)
+		d.fails++
+	}
+	return true, nil
+}
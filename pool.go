@@ -0,0 +1,101 @@
+package anneal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// eliteFormat is the version of the encoding written by WriteElite.
+const eliteFormat = 1
+
+// An eliteFile is the portable encoding of a list of Elites: JSON holding each State as encoded by a Codec.
+type eliteFile struct {
+	Format int               `json:"format"`
+	Labels map[string]string `json:"labels,omitempty"`
+	Elite  []eliteEntry      `json:"elite"`
+}
+
+type eliteEntry struct {
+	Energy float64 `json:"energy"`
+	State  []byte  `json:"state"` // encoded as base64
+}
+
+// WriteElite writes elite, such as Result.Elite, to w in a portable format, encoding the States with c,
+// so that another run, possibly in another process, can read it with ReadElite. A nil Codec means BinaryCodec.
+func WriteElite(w io.Writer, elite []Elite, c Codec) error {
+	return WriteLabeledElite(w, elite, nil, c)
+}
+
+// WriteLabeledElite is like WriteElite but also records labels, such as Result.Labels,
+// which ReadLabeledElite returns.
+func WriteLabeledElite(w io.Writer, elite []Elite, labels map[string]string, c Codec) error {
+	if c == nil {
+		c = BinaryCodec{}
+	}
+	f := eliteFile{Format: eliteFormat, Labels: labels, Elite: make([]eliteEntry, len(elite))}
+	for i, el := range elite {
+		data, err := c.Encode(el.State)
+		if err != nil {
+			return fmt.Errorf("anneal: elite State %d: %w", i, err)
+		}
+		f.Elite[i] = eliteEntry{el.Energy, data}
+	}
+	return json.NewEncoder(w).Encode(f)
+}
+
+// ReadElite reads a list of Elites written by WriteElite, decoding each State with c
+// into a new State returned by newState. A nil Codec means BinaryCodec.
+// The energies are those recorded by the writer; States whose energy depends on
+// data not encoded with them should be checked by recomputing it.
+func ReadElite(r io.Reader, c Codec, newState func() State) ([]Elite, error) {
+	elite, _, err := ReadLabeledElite(r, c, newState)
+	return elite, err
+}
+
+// ReadLabeledElite is like ReadElite but also returns the labels recorded by WriteLabeledElite, if any.
+func ReadLabeledElite(r io.Reader, c Codec, newState func() State) ([]Elite, map[string]string, error) {
+	if c == nil {
+		c = BinaryCodec{}
+	}
+	var f eliteFile
+	if err := json.NewDecoder(r).Decode(&f); err != nil {
+		return nil, nil, fmt.Errorf("anneal: reading elite: %w", err)
+	}
+	if f.Format != eliteFormat {
+		return nil, nil, fmt.Errorf("anneal: reading elite: unknown format %d", f.Format)
+	}
+	elite := make([]Elite, len(f.Elite))
+	for i, en := range f.Elite {
+		s := newState()
+		if err := c.Decode(en.State, s); err != nil {
+			return nil, nil, fmt.Errorf("anneal: elite State %d: %w", i, err)
+		}
+		elite[i] = Elite{s, en.Energy}
+	}
+	return elite, f.Labels, nil
+}
+
+var errEmptyPool = errors.New("anneal: empty elite pool")
+
+// EliteInitializer returns an Initializer that starts restarts from the States of elite in turn,
+// cycling back to the first after the last, so that an imported archive serves as a restart pool.
+// Its Initial method returns an error if elite is empty. The Initializer is safe for concurrent use.
+func EliteInitializer(elite []Elite) Initializer {
+	var (
+		mu   sync.Mutex
+		next int
+	)
+	return InitializerFunc(func() (State, error) {
+		if len(elite) == 0 {
+			return nil, errEmptyPool
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		s := elite[next].State
+		next = (next + 1) % len(elite)
+		return s, nil
+	})
+}
@@ -0,0 +1,296 @@
+/*
+Package qubo adapts quadratic unconstrained binary optimization problems and Ising models to package anneal.
+
+A Problem assigns to each vector of bits x the energy
+
+	E(x) = Σ_i Q_ii x_i + Σ_(i<j) Q_ij x_i x_j + offset
+
+and its State is an anneal.State whose neighbors differ by a single bit flip.
+Each State keeps its energy up to date incrementally, so a proposal costs time proportional to
+the number of couplings of the flipped bit rather than to the size of the problem.
+*/
+package qubo
+
+import (
+	"encoding/binary"
+	"errors"
+	"iter"
+	"math/rand"
+	"slices"
+	"sync"
+
+	"github.com/dkmccandless/anneal"
+	"github.com/dkmccandless/anneal/ising"
+)
+
+// A Term contributes V x_I x_J to the energy. Terms with I == J are linear.
+type Term struct {
+	I, J int
+	V    float64
+}
+
+// A Problem is a binary quadratic model. It is immutable after construction.
+type Problem struct {
+	diag   []float64
+	adj    []coupling // adj[off[i]:off[i+1]] lists the quadratic terms involving bit i
+	off    []int
+	offset float64
+}
+
+// A coupling is a quadratic term as seen from one of its bits.
+type coupling struct {
+	j int
+	v float64
+}
+
+// New returns the Problem on n bits with the given terms, which must refer to bits in [0, n).
+// Terms for the same pair of bits are summed regardless of their order.
+func New(n int, terms []Term) *Problem {
+	p := &Problem{diag: make([]float64, n), off: make([]int, n+1)}
+	// Count the quadratic terms of each bit, then place them contiguously in order of bit.
+	for _, t := range terms {
+		if t.I != t.J {
+			p.off[t.I+1]++
+			p.off[t.J+1]++
+		}
+	}
+	for i := range n {
+		p.off[i+1] += p.off[i]
+	}
+	p.adj = make([]coupling, p.off[n])
+	next := append([]int(nil), p.off[:n]...)
+	for _, t := range terms {
+		if t.I == t.J {
+			p.diag[t.I] += t.V
+			continue
+		}
+		p.adj[next[t.I]] = coupling{t.J, t.V}
+		p.adj[next[t.J]] = coupling{t.I, t.V}
+		next[t.I]++
+		next[t.J]++
+	}
+	return p
+}
+
+// couplings returns the quadratic terms involving bit i.
+func (p *Problem) couplings(i int) []coupling { return p.adj[p.off[i]:p.off[i+1]] }
+
+// FromMatrix returns the Problem with energy x^T q x for the square matrix q.
+// Entries q[i][j] and q[j][i] both contribute to the coupling of bits i and j.
+func FromMatrix(q [][]float64) *Problem {
+	var terms []Term
+	for i, row := range q {
+		for j, v := range row {
+			if v != 0 {
+				terms = append(terms, Term{i, j, v})
+			}
+		}
+	}
+	return New(len(q), terms)
+}
+
+// FromIsing returns the Problem equivalent to the Ising model m under the substitution s_i = 2x_i - 1,
+// so that every bit vector has the same energy as the corresponding spin configuration.
+func FromIsing(m *ising.Model) *Problem {
+	h := m.Biases()
+	terms := make([]Term, 0, len(h))
+	var offset float64
+	for i, hi := range h {
+		terms = append(terms, Term{i, i, 2 * hi})
+		offset -= hi
+	}
+	for _, c := range m.Couplings() {
+		// J s_i s_j = 4J x_i x_j - 2J x_i - 2J x_j + J
+		terms = append(terms, Term{c.I, c.J, 4 * c.V}, Term{c.I, c.I, -2 * c.V}, Term{c.J, c.J, -2 * c.V})
+		offset += c.V
+	}
+	p := New(len(h), terms)
+	p.offset = offset
+	return p
+}
+
+// Len returns the number of bits.
+func (p *Problem) Len() int { return len(p.diag) }
+
+// Energy returns the energy of the bit vector x.
+func (p *Problem) Energy(x []bool) float64 {
+	e := p.offset
+	for i, xi := range x {
+		if !xi {
+			continue
+		}
+		e += p.diag[i]
+		for _, c := range p.couplings(i) {
+			if c.j > i && x[c.j] {
+				e += c.v
+			}
+		}
+	}
+	return e
+}
+
+// Delta returns the change in energy caused by flipping bit i of x.
+func (p *Problem) Delta(x []bool, i int) float64 {
+	d := p.diag[i]
+	for _, c := range p.couplings(i) {
+		if x[c.j] {
+			d += c.v
+		}
+	}
+	if x[i] {
+		return -d
+	}
+	return d
+}
+
+// NewState returns a State of p holding x. It does not copy x.
+func (p *Problem) NewState(x []bool) *State {
+	return &State{X: x, p: p, e: p.Energy(x)}
+}
+
+// Random returns a State of p with uniformly random bits.
+func (p *Problem) Random() *State {
+	x := make([]bool, p.Len())
+	for i := range x {
+		x[i] = rand.Intn(2) == 1
+	}
+	return p.NewState(x)
+}
+
+// A State is a bit vector in the search space of a Problem.
+// It implements anneal.Recomputer, anneal.Enumerator, anneal.Hasher, anneal.Snapshotter, anneal.Seeded,
+// and anneal.Recycler by drawing the storage of its neighbors from a pool.
+type State struct {
+	X    []bool
+	p    *Problem
+	e    float64
+	rand *rand.Rand // source set by UseRand, or nil for the global source
+}
+
+// Energy returns the incrementally maintained energy of s.
+func (s *State) Energy() float64 { return s.e }
+
+// RecomputeEnergy returns the energy of s computed from scratch.
+func (s *State) RecomputeEnergy() float64 { return s.p.Energy(s.X) }
+
+// Neighbor returns a State that differs from s in one randomly chosen bit.
+func (s *State) Neighbor() anneal.State {
+	i := s.intn(len(s.X))
+	n := s.CloneInto(states.Get().(*State))
+	n.X[i] = !n.X[i]
+	n.e += s.p.Delta(s.X, i)
+	return n
+}
+
+// UseRand sets the source of randomness of s and its neighbors.
+func (s *State) UseRand(r *rand.Rand) { s.rand = r }
+
+// intn returns a random number in [0, n) from the source of s.
+func (s *State) intn(n int) int {
+	if s.rand == nil {
+		return rand.Intn(n)
+	}
+	return s.rand.Intn(n)
+}
+
+// Release returns the storage of s to the pool from which Neighbor draws.
+func (s *State) Release() { states.Put(s) }
+
+// states holds released States for reuse.
+var states = sync.Pool{New: func() any { return new(State) }}
+
+// Flip returns a State that differs from s in bit i.
+func (s *State) Flip(i int) *State {
+	x := slices.Clone(s.X)
+	x[i] = !x[i]
+	return &State{X: x, p: s.p, e: s.e + s.p.Delta(s.X, i), rand: s.rand}
+}
+
+// Neighborhood returns an iterator over the States that differ from s in one bit.
+func (s *State) Neighborhood() iter.Seq[anneal.State] {
+	return func(yield func(anneal.State) bool) {
+		for i := range s.X {
+			if !yield(s.Flip(i)) {
+				return
+			}
+		}
+	}
+}
+
+// Spins returns the spin configuration s_i = 2x_i - 1 corresponding to s.
+func (s *State) Spins() []int8 {
+	spins := make([]int8, len(s.X))
+	for i, xi := range s.X {
+		spins[i] = -1
+		if xi {
+			spins[i] = 1
+		}
+	}
+	return spins
+}
+
+// Hash returns a hash of the bits of s: the 64-bit FNV-1a hash of MarshalBinary's encoding,
+// computed without allocating it.
+func (s *State) Hash() uint64 {
+	const (
+		offset = 14695981039346656037
+		prime  = 1099511628211
+	)
+	h := uint64(offset)
+	var n [binary.MaxVarintLen64]byte
+	for _, b := range n[:binary.PutUvarint(n[:], uint64(len(s.X)))] {
+		h = (h ^ uint64(b)) * prime
+	}
+	for i := 0; i < len(s.X); i += 8 {
+		var b byte
+		for j, xi := range s.X[i:min(i+8, len(s.X))] {
+			if xi {
+				b |= 1 << j
+			}
+		}
+		h = (h ^ uint64(b)) * prime
+	}
+	return h
+}
+
+// CloneInto copies s into dst, reusing the storage of dst.X if it is large enough, and returns dst.
+// If dst is nil, it allocates a new State. Neighbor uses it to fill released States;
+// programs that manage their own pools of States can use it likewise.
+func (s *State) CloneInto(dst *State) *State {
+	if dst == nil {
+		dst = new(State)
+	}
+	dst.X = append(dst.X[:0], s.X...)
+	dst.p, dst.e, dst.rand = s.p, s.e, s.rand
+	return dst
+}
+
+// MarshalBinary encodes the bits of s.
+func (s *State) MarshalBinary() ([]byte, error) {
+	b := binary.AppendUvarint(nil, uint64(len(s.X)))
+	packed := make([]byte, (len(s.X)+7)/8)
+	for i, xi := range s.X {
+		if xi {
+			packed[i/8] |= 1 << (i % 8)
+		}
+	}
+	return append(b, packed...), nil
+}
+
+// UnmarshalBinary decodes bits encoded by MarshalBinary into s, which must already belong to a Problem of the same size.
+func (s *State) UnmarshalBinary(data []byte) error {
+	if s.p == nil {
+		return errors.New("qubo: State does not belong to a Problem")
+	}
+	n, k := binary.Uvarint(data)
+	if k <= 0 || n != uint64(s.p.Len()) || len(data[k:]) != (int(n)+7)/8 {
+		return errors.New("qubo: invalid State encoding")
+	}
+	packed := data[k:]
+	s.X = make([]bool, n)
+	for i := range s.X {
+		s.X[i] = packed[i/8]&(1<<(i%8)) != 0
+	}
+	s.e = s.p.Energy(s.X)
+	return nil
+}
@@ -0,0 +1,46 @@
+package qubo
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/dkmccandless/anneal"
+)
+
+func benchState() *State {
+	const n = 1000
+	var terms []Term
+	for i := range n {
+		terms = append(terms, Term{i, i, rand.NormFloat64()}, Term{i, (i + 1) % n, rand.NormFloat64()})
+	}
+	return New(n, terms).Random()
+}
+
+func TestNeighbor(t *testing.T) {
+	s := benchState()
+	for range 100 {
+		n := s.Neighbor().(*State)
+		if e := n.RecomputeEnergy(); math.Abs(n.Energy()-e) > 1e-9 {
+			t.Fatalf("Energy = %v, want %v", n.Energy(), e)
+		}
+		n.Release()
+	}
+}
+
+func BenchmarkNeighbor(b *testing.B) {
+	s := benchState()
+	b.ReportAllocs()
+	for b.Loop() {
+		s.Neighbor().(anneal.Recycler).Release()
+	}
+}
+
+func BenchmarkCloneInto(b *testing.B) {
+	s := benchState()
+	dst := s.CloneInto(nil)
+	b.ReportAllocs()
+	for b.Loop() {
+		s.CloneInto(dst)
+	}
+}
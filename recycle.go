@@ -0,0 +1,31 @@
+package anneal
+
+// A Recycler is a State whose storage can be reused, such as one whose Neighbor method draws the storage
+// of its neighbors from a sync.Pool, so that long runs of States backed by large slices need not leave
+// every rejected neighbor to the garbage collector.
+// Anneal calls Release on each proposal that it rejects or discards, once it holds no further reference to it,
+// and never uses the State again. It does not release proposals that became the best State,
+// unless the best State is a copy because they implement Cloner, nor any proposals if Schedule.Keep is positive.
+// An Acceptor must not retain the proposals that it is asked to decide.
+//
+// If Neighbor reuses released storage, an iteration whose proposal is rejected allocates nothing,
+// so a search that has settled into a minimum runs without allocating.
+type Recycler interface {
+	State
+
+	// Release returns the storage of the State for reuse.
+	Release()
+}
+
+// recycle releases s, a proposal that the search has discarded, if it is a Recycler that the search does not retain.
+// best reports whether s became the best State.
+func (a *annealer) recycle(s State, best bool) {
+	r, ok := s.(Recycler)
+	if !ok || a.archive != nil {
+		return
+	}
+	if _, cloned := s.(Cloner); best && !cloned {
+		return
+	}
+	r.Release()
+}
@@ -0,0 +1,149 @@
+package anneal
+
+import (
+	"math/rand"
+	"runtime"
+	"sync"
+	"testing"
+)
+
+// A pooled is a Recycler on the integers with energy x^2 and neighbors x ± 1
+// that counts the States it makes, that Anneal adopts, and that Anneal releases.
+type pooled struct {
+	x        int
+	released bool
+	c        *poolCounts
+}
+
+type poolCounts struct {
+	mu                      sync.Mutex
+	made, adopted, released int
+}
+
+func (p *pooled) Energy() float64 {
+	if p.released {
+		panic("Energy called on a released State")
+	}
+	return float64(p.x * p.x)
+}
+
+func (p *pooled) Neighbor() State {
+	p.c.mu.Lock()
+	p.c.made++
+	p.c.mu.Unlock()
+	return &pooled{x: p.x + 2*rand.Intn(2) - 1, c: p.c}
+}
+
+func (p *pooled) OnAccept(prev, next State) {
+	p.c.mu.Lock()
+	p.c.adopted++
+	p.c.mu.Unlock()
+}
+
+func (p *pooled) Release() {
+	if p.released {
+		panic("State released twice")
+	}
+	p.released = true
+	p.c.mu.Lock()
+	p.c.released++
+	p.c.mu.Unlock()
+}
+
+func TestPolishRecycles(t *testing.T) {
+	c := new(poolCounts)
+	r, err := Run(&pooled{x: 1000, c: c}, WithIterations(1), WithWorkers(4), WithPolish(100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Energy != 0 {
+		t.Fatalf("Energy = %v, want 0", r.Energy)
+	}
+	// Every proposal must be either adopted or released.
+	if lost := c.made - c.adopted - c.released; lost > 0 {
+		t.Errorf("made %d States, adopted %d, released %d: %d neither adopted nor released",
+			c.made, c.adopted, c.released, lost)
+	}
+}
+
+// A slot is a Recycler on the integers with energy x^2 and neighbors x ± 1,
+// whose neighbors reuse released slots from a free list.
+type slot struct {
+	x    int
+	free *freeList
+}
+
+type freeList struct {
+	mu    sync.Mutex
+	slots []*slot
+}
+
+func (s *slot) Energy() float64 { return float64(s.x * s.x) }
+
+func (s *slot) Neighbor() State {
+	var n *slot
+	s.free.mu.Lock()
+	if k := len(s.free.slots); k > 0 {
+		n, s.free.slots = s.free.slots[k-1], s.free.slots[:k-1]
+	}
+	s.free.mu.Unlock()
+	if n == nil {
+		n = &slot{free: s.free}
+	}
+	n.x = s.x + 2*rand.Intn(2) - 1
+	return n
+}
+
+func (s *slot) Release() {
+	s.free.mu.Lock()
+	s.free.slots = append(s.free.slots, s)
+	s.free.mu.Unlock()
+}
+
+func TestSteadyStateAllocs(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		opts []Option
+	}{
+		{"sprint", nil},
+		{"step", []Option{WithAudit(1 << 30)}}, // an audit prevents sprinting
+		{"Workers", []Option{WithWorkers(2)}},
+	} {
+		// At this temperature every uphill proposal is rejected, so once the search has settled at 0,
+		// every proposal is released and reused.
+		opts := append([]Option{WithIterations(1e12), WithTemperatures(1e-9, 1e-10)}, tt.opts...)
+		an, err := New(&slot{x: 20, free: new(freeList)}, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for an.Current().(*slot).x != 0 {
+			if !an.Step() {
+				t.Fatalf("%s: search ended at %v", tt.name, an.Current().(*slot).x)
+			}
+		}
+		// Any allocation per step or per proposal would appear many times over;
+		// the runtime's own bookkeeping, such as its semaphore queues, accounts for at most a few.
+		const steps = 3000
+		if n := mallocs(func() {
+			for range steps {
+				an.Step()
+			}
+		}); n > maxRuntimeMallocs {
+			t.Errorf("%s: %d allocations in %d steps, want at most %d", tt.name, n, steps, maxRuntimeMallocs)
+		}
+		an.Close()
+	}
+}
+
+// maxRuntimeMallocs is the number of heap allocations that the runtime may make for its own purposes
+// while a test measures allocations, such as for the queues of goroutines blocked on a semaphore.
+const maxRuntimeMallocs = 10
+
+// mallocs returns the number of heap allocations made while f runs.
+func mallocs(f func()) uint64 {
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	f()
+	runtime.ReadMemStats(&after)
+	return after.Mallocs - before.Mallocs
+}
@@ -0,0 +1,149 @@
+package anneal
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// decisionHeader begins every decision log written for Schedule.Decisions.
+const decisionHeader = "anneal-decisions-1\n"
+
+// Kinds of records in a decision log. Each record is a kind byte followed by 8 bytes in little-endian order:
+// for decisions, the energy of the proposal as an IEEE 754 float64, and for picks, the index of the Initializer.
+const (
+	recordReject byte = iota
+	recordAccept
+	recordPick
+)
+
+// A decisionLog writes a decision log.
+type decisionLog struct {
+	w   *bufio.Writer
+	buf [9]byte
+}
+
+func newDecisionLog(w io.Writer) *decisionLog {
+	l := &decisionLog{w: bufio.NewWriter(w)}
+	l.w.WriteString(decisionHeader)
+	return l
+}
+
+// write records a record of the given kind and value.
+func (l *decisionLog) write(kind byte, v uint64) {
+	l.buf[0] = kind
+	binary.LittleEndian.PutUint64(l.buf[1:], v)
+	l.w.Write(l.buf[:])
+}
+
+// decision records whether a proposal of energy e was adopted.
+func (l *decisionLog) decision(e float64, accepted bool) {
+	kind := recordReject
+	if accepted {
+		kind = recordAccept
+	}
+	l.write(kind, math.Float64bits(e))
+}
+
+// ErrDiverged is returned by Replay when the search departs from the trajectory recorded in the decision log.
+var ErrDiverged = errors.New("anneal: replay diverged from the decision log")
+
+// A replayer reads a decision log in place of making random decisions.
+type replayer struct {
+	r   *bufio.Reader
+	n   int // number of records read
+	buf [9]byte
+}
+
+func newReplayer(r io.Reader) (*replayer, error) {
+	br := bufio.NewReader(r)
+	header := make([]byte, len(decisionHeader))
+	if _, err := io.ReadFull(br, header); err != nil || string(header) != decisionHeader {
+		return nil, errors.New("anneal: not a decision log")
+	}
+	return &replayer{r: br}, nil
+}
+
+// next reads the next record, which must be of one of the given kinds.
+func (rp *replayer) next(kinds ...byte) (byte, uint64, error) {
+	if _, err := io.ReadFull(rp.r, rp.buf[:]); err != nil {
+		if err == io.EOF {
+			return 0, 0, fmt.Errorf("%w: the log ends after %d records", ErrDiverged, rp.n)
+		}
+		return 0, 0, fmt.Errorf("anneal: reading decision log: %w", err)
+	}
+	rp.n++
+	kind, v := rp.buf[0], binary.LittleEndian.Uint64(rp.buf[1:])
+	for _, k := range kinds {
+		if kind == k {
+			return kind, v, nil
+		}
+	}
+	return 0, 0, fmt.Errorf("%w: unexpected record %d of kind %d", ErrDiverged, rp.n, kind)
+}
+
+// decide returns the recorded decision for a proposal of energy e.
+func (rp *replayer) decide(e float64) (bool, error) {
+	kind, v, err := rp.next(recordReject, recordAccept)
+	if err != nil {
+		return false, err
+	}
+	if math.Float64bits(e) != v {
+		return false, fmt.Errorf("%w: record %d: proposal has energy %v, but the log records %v",
+			ErrDiverged, rp.n, e, math.Float64frombits(v))
+	}
+	return kind == recordAccept, nil
+}
+
+// pick returns the recorded index of an Initializer among n.
+func (rp *replayer) pick(n int) (int, error) {
+	_, v, err := rp.next(recordPick)
+	if err != nil {
+		return 0, err
+	}
+	if v >= uint64(n) {
+		return 0, fmt.Errorf("%w: record %d: Initializer %d of %d", ErrDiverged, rp.n, v, n)
+	}
+	return int(v), nil
+}
+
+// done reports an error if records remain unread.
+func (rp *replayer) done() error {
+	if _, err := rp.r.Peek(1); err == io.EOF {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("anneal: reading decision log: %w", err)
+	}
+	return fmt.Errorf("%w: the search ended after %d records, before the end of the log", ErrDiverged, rp.n)
+}
+
+// Replay repeats a search recorded by Schedule.Decisions: it anneals s as Run does,
+// but takes every acceptance decision and choice of Initializer from the decision log read from r
+// rather than making it at random. Given the same input State and Schedule, and States whose proposals
+// are themselves reproducible, for example because they draw from a seeded source, it reproduces
+// the recorded trajectory exactly, which helps in debugging pathological runs and in writing regression tests.
+// Runs of fixed Duration end after different numbers of iterations, so they cannot be replayed exactly.
+//
+// Replay returns an error wrapping ErrDiverged if a proposal's energy differs from the one recorded,
+// or if the search and the log do not end together, together with the Result of the search until then.
+func Replay(s State, r io.Reader, opts ...Option) (Result, error) {
+	rp, err := newReplayer(r)
+	if err != nil {
+		return Result{}, err
+	}
+	an, err := New(s, opts...)
+	if err != nil {
+		return Result{}, err
+	}
+	defer an.Close()
+	an.a.replay = rp
+	for an.Step() {
+	}
+	if err := an.Err(); err != nil {
+		return an.Result(), err
+	}
+	return an.Result(), rp.done()
+}
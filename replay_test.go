@@ -0,0 +1,90 @@
+package anneal
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// replayProblem is a multimodal function, so that a search from a fixed point depends on every decision.
+var replayProblem = &VectorProblem{
+	Func: func(x []float64) float64 {
+		var sum float64
+		for _, xi := range x {
+			sum += xi*xi - 10*math.Cos(2*math.Pi*xi)
+		}
+		return sum
+	},
+	Step: 1,
+}
+
+func replayStart() State { return replayProblem.NewState([]float64{3, -2, 4}) }
+
+func TestReplay(t *testing.T) {
+	initial := InitializerFunc(func() (State, error) { return replayStart(), nil })
+	for _, test := range []struct {
+		name string
+		opts []Option
+	}{
+		{"run", []Option{WithIterations(2000), WithTemperatures(10, 0.01), WithSeed(1)}},
+		{"restarts", []Option{WithIterations(500), WithTemperatures(10, 0.01), WithSeed(2), WithRestarts(3, 0), WithInitializers(initial, initial)}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			var log bytes.Buffer
+			want, err := Run(replayStart(), append(slices.Clip(test.opts), WithDecisions(&log))...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := Replay(replayStart(), &log, test.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if x, y := got.Best.(*VectorState).X, want.Best.(*VectorState).X; !slices.Equal(x, y) {
+				t.Errorf("Best = %v, want %v", x, y)
+			}
+			if got.Energy != want.Energy {
+				t.Errorf("Energy = %v, want %v", got.Energy, want.Energy)
+			}
+			if got.Evaluations != want.Evaluations {
+				t.Errorf("Evaluations = %d, want %d", got.Evaluations, want.Evaluations)
+			}
+		})
+	}
+}
+
+func TestReplayMismatch(t *testing.T) {
+	opts := []Option{WithIterations(2000), WithTemperatures(10, 0.01), WithSeed(1)}
+	var log bytes.Buffer
+	if _, err := Run(replayStart(), append(slices.Clip(opts), WithDecisions(&log))...); err != nil {
+		t.Fatal(err)
+	}
+	recorded := log.Bytes()
+	for _, test := range []struct {
+		name  string
+		start State
+		log   []byte
+		opts  []Option
+	}{
+		{"other seed", replayStart(), recorded, []Option{WithIterations(2000), WithTemperatures(10, 0.01), WithSeed(2)}},
+		{"other start", replayProblem.NewState([]float64{3, -2, 5}), recorded, opts},
+		{"longer search", replayStart(), recorded, []Option{WithIterations(3000), WithTemperatures(10, 0.01), WithSeed(1)}},
+		{"shorter search", replayStart(), recorded, []Option{WithIterations(1000), WithTemperatures(10, 0.01), WithSeed(1)}},
+		{"truncated log", replayStart(), recorded[:len(decisionHeader)+9*100], opts},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := Replay(test.start, bytes.NewReader(test.log), test.opts...); !errors.Is(err, ErrDiverged) {
+				t.Errorf("Replay error = %v, want ErrDiverged", err)
+			}
+		})
+	}
+
+	if _, err := Replay(replayStart(), strings.NewReader("not a log"), opts...); err == nil || errors.Is(err, ErrDiverged) {
+		t.Errorf("Replay of a file that is not a decision log: error = %v", err)
+	}
+	if _, err := Replay(replayStart(), bytes.NewReader(recorded[:len(recorded)-1]), opts...); err == nil {
+		t.Error("Replay of a log ending within a record succeeded")
+	}
+}
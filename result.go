@@ -0,0 +1,75 @@
+package anneal
+
+import "errors"
+
+// A Result describes the outcome of an annealing run.
+type Result struct {
+	Best   State   // best State encountered
+	Energy float64 // energy of Best
+
+	// Certificate attests that Best is a local optimum of its enumerated neighborhood.
+	// It is nil unless the run ended with a complete systematic scan; see Schedule.Scan.
+	Certificate *Certificate
+
+	// Drift is the first discrepancy detected by the energy audit, or nil if none was detected; see Schedule.Audit.
+	Drift *Drift
+
+	// Duplicates describes how often proposals duplicated recently seen States,
+	// or is nil if they were not tracked; see Schedule.Recent.
+	Duplicates *DuplicateStats
+
+	// Elite lists the best distinct States encountered, in order of increasing energy,
+	// or is nil if they were not retained; see Schedule.Keep.
+	Elite []Elite
+
+	// Initializers describes the restarts begun from each of Schedule.Initializers, in the same order,
+	// or is nil if there were none.
+	Initializers []InitializerStats
+
+	// Moves describes the proposals made with each operator of a Mover, in order,
+	// or is nil if the input State is not a Mover.
+	Moves []MoveStats
+
+	// History lists the States most recently adopted, from oldest to newest,
+	// or is nil if they were not recorded; see Schedule.History.
+	History []HistoryEntry
+
+	Evaluations int // number of energy evaluations performed; see Schedule.Evals
+
+	// BestTemperature is the temperature at which Best was proposed, in the units of Schedule.Ti.
+	// It is zero if Best is the input State or was found by a systematic scan, the finishing descent, or an Initializer.
+	BestTemperature float64
+
+	// Temperature is the temperature at which the most recent run ended, or at which it stands
+	// if the search is incomplete, in the units of Schedule.Ti.
+	Temperature float64
+
+	Labels map[string]string // labels identifying the search; see Schedule.Labels
+
+	Usage    Usage   // resources consumed by the whole call, including setup
+	RunUsage []Usage // resources consumed by each run, counting restarts, in order
+
+	temp float64 // Temperature in units of energy
+}
+
+// Continue resumes the search described by r, as for a run that has not converged:
+// it anneals r.Best as Run does with opts, but begins at the temperature at which the search of r ended
+// and cools by the ratio of the Schedule's Tf to its Ti, in place of cooling from Ti to Tf,
+// so that successive continuations extend a single geometric descent of the temperature.
+// If either the temperature or the energy of r.Best is zero, the Schedule's temperatures are used unchanged.
+// The Result describes the continuation alone: its Evaluations and RunUsage, for example, exclude those of r.
+func (r Result) Continue(opts ...Option) (Result, error) {
+	if r.Best == nil {
+		return Result{}, errors.New("anneal: Continue of a Result without a best State")
+	}
+	scale := energyScale(r.Energy)
+	if r.temp <= 0 || scale == 0 {
+		return Run(r.Best, opts...)
+	}
+	Ti := r.temp / scale
+	resume := optionFunc(func(sch *Schedule) {
+		sch.Tf *= Ti / sch.Ti
+		sch.Ti = Ti
+	})
+	return Run(r.Best, append(opts[:len(opts):len(opts)], resume)...)
+}
@@ -0,0 +1,150 @@
+package anneal
+
+import (
+	"fmt"
+	"math"
+	"slices"
+)
+
+// LandscapeStats describes the energy landscape around a State, as sampled by a random walk, and suggests a Schedule for it.
+type LandscapeStats struct {
+	Steps int // number of steps of the walk whose energies were finite
+
+	Mean, StdDev float64 // mean and standard deviation of the energies visited
+	Min, Max     float64 // lowest and highest energies visited
+
+	MeanDelta float64 // mean absolute difference in energy between successive States
+	MeanUp    float64 // mean increase in energy of the steps that increased it
+	SmallUp   float64 // 5th percentile of the increases in energy, a typical difference between adjacent States
+
+	// Correlation is the correlation length of the walk: the number of steps over which the correlation
+	// of energies decays by a factor of e, estimated from the correlation ρ of successive energies as -1/ln ρ.
+	// Landscapes with long correlation lengths are smooth, and those with short ones rugged.
+	// It is zero if successive energies are uncorrelated, and +Inf if they are perfectly correlated.
+	Correlation float64
+
+	// Ti and Tf are the suggested temperatures as multiples of the input State's energy, as in a Schedule,
+	// and Iter the suggested number of iterations. They are zero if the input State's energy is zero or infinite,
+	// or if no step increased the energy; see Sample.
+	Ti, Tf float64
+	Iter   int
+}
+
+// Suggested temperatures and lengths of Sample.
+const (
+	sampleAcceptUp    = 0.8  // probability of adopting a mean increase at Ti
+	sampleAcceptSmall = 1e-3 // probability of adopting a small increase at Tf
+
+	// sampleSweeps is the number of correlation lengths to traverse while the temperature falls by a factor of e.
+	sampleSweeps = 1000
+)
+
+// Sample performs a random walk of n steps from s, adopting every neighbor of finite energy, and returns statistics
+// of the energies that it visits. Proposals of Tempered States are made at temperature 1 in the units of a Schedule.
+//
+// It turns the guidance of the package documentation into suggestions: Ti is the temperature at which
+// a mean increase in energy is adopted with probability 0.8, so that the search begins almost freely;
+// Tf the temperature at which a small increase is adopted with probability 0.001, so that it ends almost greedily;
+// and Iter allows 1000 correlation lengths, and at least 1000 iterations, for each fall of the temperature by a factor of e,
+// so that the search stays near equilibrium. The suggestions are starting points for Tune, not guarantees.
+func Sample(s State, n int) (LandscapeStats, error) {
+	e, err := energy(s)
+	if err != nil {
+		return LandscapeStats{}, fmt.Errorf("anneal: input State: %w", err)
+	}
+	scale := energyScale(e)
+	var (
+		st         = LandscapeStats{Min: math.Inf(1), Max: math.Inf(-1)}
+		energies   []float64
+		ups        []float64
+		sumDelta   float64
+		cur, ecur  = s, e
+		finiteThen = finite(e)
+	)
+	if finiteThen {
+		energies = append(energies, e)
+	}
+	for range max(n, 1) {
+		p := evaluate(cur, scale, -1, 1)
+		if p.err != nil {
+			return LandscapeStats{}, p.err
+		}
+		if !finite(p.e) {
+			continue
+		}
+		if finiteThen {
+			d := p.e - ecur
+			sumDelta += math.Abs(d)
+			if d > 0 {
+				ups = append(ups, d)
+			}
+		}
+		notify(cur, p.s)
+		cur, ecur, finiteThen = p.s, p.e, true
+		energies = append(energies, p.e)
+		st.Steps++
+	}
+	if len(energies) == 0 {
+		return st, nil
+	}
+
+	var sum float64
+	for _, x := range energies {
+		sum += x
+		st.Min, st.Max = min(st.Min, x), max(st.Max, x)
+	}
+	st.Mean = sum / float64(len(energies))
+	var vari, cov float64
+	for i, x := range energies {
+		vari += (x - st.Mean) * (x - st.Mean)
+		if i > 0 {
+			cov += (x - st.Mean) * (energies[i-1] - st.Mean)
+		}
+	}
+	st.StdDev = math.Sqrt(vari / float64(len(energies)))
+	if st.Steps > 0 {
+		st.MeanDelta = sumDelta / float64(st.Steps)
+	}
+	switch rho := cov / vari; {
+	case vari == 0 || rho >= 1:
+		st.Correlation = math.Inf(1)
+	case rho > 0:
+		st.Correlation = -1 / math.Log(rho)
+	}
+	if len(ups) == 0 {
+		return st, nil
+	}
+	slices.Sort(ups)
+	for _, d := range ups {
+		st.MeanUp += d
+	}
+	st.MeanUp /= float64(len(ups))
+	st.SmallUp = ups[len(ups)/20]
+
+	if scale == 0 || math.IsInf(e, 0) {
+		return st, nil
+	}
+	Ti := -st.MeanUp / math.Log(sampleAcceptUp)
+	Tf := min(-st.SmallUp/math.Log(sampleAcceptSmall), Ti/10)
+	st.Ti, st.Tf = Ti/scale, Tf/scale
+	corr := st.Correlation
+	if math.IsInf(corr, 1) {
+		corr = float64(st.Steps)
+	}
+	st.Iter = int(math.Ceil(math.Log(Ti/Tf) * max(sampleSweeps*corr, 1000)))
+	return st, nil
+}
+
+// Schedule returns a Schedule with the default values of NewSchedule and the suggested Ti, Tf, and Iter, if any.
+func (st LandscapeStats) Schedule() *Schedule {
+	sch := NewSchedule()
+	if st.Iter > 0 {
+		sch.Ti, sch.Tf, sch.Iter = st.Ti, st.Tf, st.Iter
+	}
+	return sch
+}
+
+func (st LandscapeStats) String() string {
+	return fmt.Sprintf("%d steps, energy %v ± %v in [%v, %v], mean |ΔE| %v, mean increase %v, small increase %v, correlation length %.3g; suggest Ti %.3g, Tf %.3g, Iter %d",
+		st.Steps, st.Mean, st.StdDev, st.Min, st.Max, st.MeanDelta, st.MeanUp, st.SmallUp, st.Correlation, st.Ti, st.Tf, st.Iter)
+}
@@ -0,0 +1,42 @@
+package anneal
+
+import "math"
+
+// A Scaler is a State whose proposals have an adjustable size, such as the step of a move in a continuous space.
+// If Schedule.AcceptTarget is positive, Anneal controls the size by feedback on the rate at which proposals are adopted:
+// after every window of Schedule.ScaleWindow iterations, it multiplies a scaling factor by exp(2(r - AcceptTarget)),
+// where r is the fraction of the window's proposals that were adopted, so that proposals shrink when too few
+// are adopted and grow when too many are. The factor is 1 at the start of each run and stays within [1e-9, 1e9].
+// Anneal calls Scale with the factor on the current State before proposing each neighbor of it.
+type Scaler interface {
+	State
+
+	// Scale sets the factor by which to multiply the sizes of the State's subsequent proposals.
+	// Neighbors of the State should inherit the factor.
+	Scale(f float64)
+}
+
+// defaultScaleWindow is the number of iterations between adjustments of the scaling factor if Schedule.ScaleWindow is zero.
+const defaultScaleWindow = 100
+
+// Bounds of the scaling factor of a Scaler.
+const (
+	minScale = 1e-9
+	maxScale = 1e9
+)
+
+// rescale adjusts the scaling factor of the run c at the end of each window and applies it to the current State.
+func (a *annealer) rescale(c *chain) {
+	sc, ok := c.s.(Scaler)
+	if !ok {
+		return
+	}
+	if c.i >= c.nextScale {
+		if n := c.i - c.scaleIter; n > 0 {
+			r := float64(c.accepted-c.scaleAcc) / float64(n)
+			c.factor = min(max(c.factor*math.Exp(2*(r-a.scaleTo)), minScale), maxScale)
+		}
+		c.scaleAcc, c.scaleIter, c.nextScale = c.accepted, c.i, c.i+a.scaleWin
+	}
+	sc.Scale(c.factor)
+}
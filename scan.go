@@ -0,0 +1,84 @@
+package anneal
+
+import (
+	"fmt"
+	"iter"
+)
+
+// An Enumerator is a State whose neighborhood can be enumerated exhaustively.
+// Anneal can use it to verify that the State it returns is a local optimum; see Schedule.Scan.
+type Enumerator interface {
+	State
+
+	// Neighborhood returns an iterator over all States adjacent to the State.
+	// The States it yields must satisfy the same requirements as those returned by Neighbor.
+	Neighborhood() iter.Seq[State]
+}
+
+// A Certificate states that a State is locally optimal with respect to its enumerated neighborhood:
+// no State yielded by its Neighborhood has lower energy.
+type Certificate struct {
+	Energy    float64 // energy of the certified State
+	Verified  int     // number of neighbors verified in the final, complete scan
+	Scans     int     // number of scans performed during the descent, including the final one
+	Evaluated int     // number of neighbors evaluated during the descent
+}
+
+func (c *Certificate) String() string {
+	return fmt.Sprintf("locally optimal at energy %v: verified %d of %d neighbors evaluated in %d scans",
+		c.Energy, c.Verified, c.Evaluated, c.Scans)
+}
+
+// scan performs first-improvement descent from s, which is the best State, until no neighbor has lower energy.
+// It updates the best State as it goes and returns the local optimum and its energy.
+// If the descent completes on the best State, it records a Certificate for it.
+func (a *annealer) scan(s State, e float64) (State, float64, error) {
+	var c Certificate
+	best := true // whether s is the best State
+	for {
+		en, ok := s.(Enumerator)
+		if !ok {
+			return s, e, nil
+		}
+		c.Scans++
+		c.Verified = 0
+		improved := false
+		for n := range en.Neighborhood() {
+			if a.stopped() {
+				return s, e, nil
+			}
+			c.Evaluated++
+			r := a.samples(0)
+			a.evals += r
+			ne, err := meanEnergy(n, r)
+			if err != nil {
+				return s, e, err
+			}
+			if ok, err := a.admit(ne); err != nil {
+				return s, e, err
+			} else if !ok {
+				c.Verified++
+				continue
+			}
+			if a.archive != nil {
+				a.archive.offer(n, ne)
+			}
+			improved = less(n, ne, s, e)
+			a.log(s, n, ne, LandscapeConsidered|landscapeFlag(improved))
+			if improved {
+				notify(s, n)
+				s, e = n, ne
+				break
+			}
+			c.Verified++
+		}
+		if !improved {
+			if best {
+				c.Energy = e
+				a.cert = &c
+			}
+			return s, e, nil
+		}
+		best = a.promote(s, e)
+	}
+}
@@ -0,0 +1,101 @@
+package anneal
+
+import (
+	"encoding/binary"
+	"iter"
+	"math/rand"
+	"testing"
+)
+
+// A line is a State on the integers whose energy is the square of its position.
+type line int
+
+func (x line) Energy() float64 { return float64(x * x) }
+
+func (x line) Neighbor() State { return x + line(2*rand.Intn(2)-1) }
+
+func (x line) Neighborhood() iter.Seq[State] {
+	return func(yield func(State) bool) {
+		_ = yield(x-1) && yield(x+1)
+	}
+}
+
+func TestScanPromotes(t *testing.T) {
+	s := line(3)
+	a := newAnnealer(s, s.Energy(), NewSchedule())
+	got, _, err := a.scan(s, s.Energy())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != line(0) {
+		t.Errorf("scan returned %v, want 0", got)
+	}
+	if a.best != line(0) || a.ebest != 0 {
+		t.Errorf("best State is %v of energy %v, want 0 of energy 0", a.best, a.ebest)
+	}
+	if a.cert == nil {
+		t.Fatal("no Certificate")
+	}
+	if a.cert.Energy != a.ebest {
+		t.Errorf("Certificate energy %v differs from best energy %v", a.cert.Energy, a.ebest)
+	}
+	if a.cert.Scans != 4 {
+		t.Errorf("Certificate records %d scans, want 4", a.cert.Scans)
+	}
+}
+
+func TestScanResult(t *testing.T) {
+	r, err := Run(line(20), WithIterations(100), WithTemperatures(1e-3, 1e-6), WithScan(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Certificate == nil {
+		t.Fatal("no Certificate")
+	}
+	if r.Energy != 0 || r.Certificate.Energy != r.Energy {
+		t.Errorf("Best has energy %v and the Certificate %v, want 0", r.Energy, r.Certificate.Energy)
+	}
+}
+
+// A snapLine is a line that implements Snapshotter, so that History records the States it adopts.
+type snapLine struct{ x line }
+
+func (s *snapLine) Energy() float64 { return s.x.Energy() }
+
+func (s *snapLine) Neighbor() State { return &snapLine{s.x.Neighbor().(line)} }
+
+func (s *snapLine) Neighborhood() iter.Seq[State] {
+	return func(yield func(State) bool) {
+		_ = yield(&snapLine{s.x - 1}) && yield(&snapLine{s.x + 1})
+	}
+}
+
+func (s *snapLine) MarshalBinary() ([]byte, error) { return binary.AppendVarint(nil, int64(s.x)), nil }
+
+func (s *snapLine) UnmarshalBinary(data []byte) error {
+	x, _ := binary.Varint(data)
+	s.x = line(x)
+	return nil
+}
+
+func TestScanAdopts(t *testing.T) {
+	// The temperature is below the threshold of the scan from the first iteration.
+	s := &snapLine{5}
+	a := newAnnealer(s, s.Energy(), configure([]Option{WithScan(10), WithHistory(4)}))
+	c := a.begin(s, s.Energy(), false)
+	for {
+		more, err := a.step(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !more {
+			break
+		}
+	}
+	if x := c.s.(*snapLine).x; x != 0 || c.e != 0 || c.f != 0 {
+		t.Errorf("run ended at %d with energies e = %v, f = %v, want 0", x, c.e, c.f)
+	}
+	if h := a.historyEntries(); len(h) == 0 || h[len(h)-1].Energy != 0 {
+		t.Errorf("History = %v, want the local optimum last", h)
+	}
+}
@@ -0,0 +1,80 @@
+package anneal
+
+import (
+	"math/rand"
+	randv2 "math/rand/v2"
+)
+
+// A Seeded is a State that draws the randomness of its proposals from a source provided by the search,
+// so that a search with Schedule.UseSeed is reproducible; see Schedule.Seed.
+// The States of package anneal are Seeded, and draw from the global source until they are given another.
+// The functions of their problems, such as TreeProblem.Grow, draw from whatever source they choose.
+type Seeded interface {
+	State
+
+	// UseRand sets the source from which the State, the neighbors it proposes, and theirs in turn draw their randomness.
+	// The source is not safe for concurrent use.
+	UseRand(r *rand.Rand)
+}
+
+// Streams of the PCG generators seeded by Schedule.Seed.
+const (
+	decisionStream = 0x9e3779b97f4a7c15 // acceptance decisions and choices of Initializers
+	stateStream    = 0xbf58476d1ce4e5b9 // proposals of Seeded States
+	ensembleStream = 0x94d049bb133111eb // perturbations and choices of operators of an Ensemble
+)
+
+// newSeededRand returns a source of randomness generating the given stream of a PCG generator seeded by seed.
+func newSeededRand(seed int64, stream uint64) *rand.Rand {
+	return rand.New(&pcgSource{randv2.NewPCG(uint64(seed), stream), stream})
+}
+
+// A pcgSource adapts a PCG generator of package math/rand/v2 to the Source64 interface of package math/rand.
+type pcgSource struct {
+	pcg    *randv2.PCG
+	stream uint64
+}
+
+func (s *pcgSource) Uint64() uint64  { return s.pcg.Uint64() }
+func (s *pcgSource) Int63() int64    { return int64(s.pcg.Uint64() >> 1) }
+func (s *pcgSource) Seed(seed int64) { s.pcg.Seed(uint64(seed), s.stream) }
+
+// A source is the source of randomness of a State of package anneal:
+// the one set by UseRand, or the global source if r is nil.
+type source struct{ r *rand.Rand }
+
+func (s source) Intn(n int) int {
+	if s.r == nil {
+		return rand.Intn(n)
+	}
+	return s.r.Intn(n)
+}
+
+func (s source) Float64() float64 {
+	if s.r == nil {
+		return rand.Float64()
+	}
+	return s.r.Float64()
+}
+
+func (s source) NormFloat64() float64 {
+	if s.r == nil {
+		return rand.NormFloat64()
+	}
+	return s.r.NormFloat64()
+}
+
+func (s source) Shuffle(n int, swap func(i, j int)) {
+	if s.r == nil {
+		rand.Shuffle(n, swap)
+		return
+	}
+	s.r.Shuffle(n, swap)
+}
+
+// provide gives s the source of randomness for Seeded States, if s is one and a source is in use.
+func (a *annealer) provide(s State) {
+	if sd, ok := s.(Seeded); ok && a.srand != nil {
+		sd.UseRand(a.srand)
+	}
+}
@@ -0,0 +1,87 @@
+package anneal
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"math/rand"
+)
+
+// SelfTest checks the acceptance rule of the annealing engine against exact statistical mechanics.
+// It samples a ring of Ising spins at several fixed temperatures using the same proposal and acceptance code
+// as Anneal, and compares the observed frequency of each energy level with the Boltzmann distribution.
+// It returns an error describing the first discrepancy, or nil if the sampled statistics agree.
+//
+// SelfTest takes on the order of a second to run.
+func SelfTest() error {
+	const iter = 1e6
+	for _, T := range []float64{0.5, 1, 3} {
+		hist := make([]int, isingSpins/2+1)
+		s := isingRing{hist: hist}
+		a := newAnnealer(s, s.Energy(), &Schedule{Iter: iter, Ti: 1, Tf: 1})
+		a.T0 = T // Ti == Tf keeps the temperature constant
+		err := a.run(s, s.Energy(), false)
+		a.close()
+		if err != nil {
+			return err
+		}
+		for w, want := range isingLevels(T) {
+			got := float64(hist[w]) / iter
+			// The standard error of each frequency is well below 0.005 given the chain's autocorrelation.
+			if math.Abs(got-want) > 0.01 {
+				return fmt.Errorf("anneal: self-test at T = %v: energy %v has frequency %.4f, want %.4f",
+					T, isingEnergy(2*w), got, want)
+			}
+		}
+	}
+	return nil
+}
+
+// isingSpins is the number of spins in the self-test ring.
+const isingSpins = 8
+
+// An isingRing is a ring of spins with ferromagnetic nearest-neighbor coupling, represented as a bit mask.
+// It counts, by number of domain wall pairs, the States from which a neighbor is proposed,
+// which for a single sequential chain is the histogram of visited States.
+type isingRing struct {
+	spins uint8
+	hist  []int
+}
+
+// walls returns the number of domain walls, which is always even.
+func (r isingRing) walls() int {
+	return bits.OnesCount8(r.spins ^ bits.RotateLeft8(r.spins, 1))
+}
+
+func (r isingRing) Energy() float64 { return isingEnergy(r.walls()) }
+
+func (r isingRing) Neighbor() State {
+	r.hist[r.walls()/2]++
+	return isingRing{spins: r.spins ^ 1<<rand.Intn(isingSpins), hist: r.hist}
+}
+
+// isingEnergy returns the energy of a ring with the given number of domain walls.
+func isingEnergy(walls int) float64 { return float64(2*walls - isingSpins) }
+
+// isingLevels returns the exact probability at temperature T of having 2w domain walls, for each w.
+func isingLevels(T float64) []float64 {
+	p := make([]float64, isingSpins/2+1)
+	var z float64
+	for w := range p {
+		// There are 2 * C(n, 2w) configurations with 2w walls.
+		p[w] = 2 * binomial(isingSpins, 2*w) * math.Exp(-isingEnergy(2*w)/T)
+		z += p[w]
+	}
+	for w := range p {
+		p[w] /= z
+	}
+	return p
+}
+
+func binomial(n, k int) float64 {
+	c := 1.0
+	for i := 1; i <= k; i++ {
+		c = c * float64(n-k+i) / float64(i)
+	}
+	return c
+}
@@ -0,0 +1,100 @@
+package anneal
+
+import (
+	"math"
+	"sync"
+	"sync/atomic"
+)
+
+// A Shared holds the best State found by cooperating searches that run concurrently,
+// such as the independent starts of a multi-start search of a large instance,
+// so that each can adopt the best State that any of them has found; see Schedule.Shared:
+//
+//	sh := anneal.NewShared()
+//	for i := range n {
+//		go func() {
+//			results[i], errs[i] = anneal.Run(start(i), anneal.WithShared(sh, 10000))
+//		}()
+//	}
+//
+// Searches that adopt a State from a Shared use it concurrently with the search that found it,
+// so the State must be safe for concurrent use, as States are that Neighbor does not modify.
+// States that implement Cloner are cloned when they are adopted, as are the best States offered.
+// A Shared is safe for concurrent use.
+type Shared struct {
+	bits atomic.Uint64 // energy of best, for comparison without locking
+
+	mu   sync.Mutex
+	best State
+	e    float64
+}
+
+// NewShared returns an empty Shared.
+func NewShared() *Shared {
+	sh := new(Shared)
+	sh.bits.Store(math.Float64bits(math.Inf(1)))
+	sh.e = math.Inf(1)
+	return sh
+}
+
+// Best returns the best State offered to sh and its energy, or nil and +Inf if none has been.
+func (sh *Shared) Best() (State, float64) {
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	return sh.best, sh.e
+}
+
+// energy returns the energy of the best State without locking.
+func (sh *Shared) energy() float64 { return math.Float64frombits(sh.bits.Load()) }
+
+// offer makes s, whose energy is e, the best State of sh if it is better. s must not be modified afterward.
+func (sh *Shared) offer(s State, e float64) {
+	if !(e <= sh.energy()) {
+		return
+	}
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	if sh.best != nil && !less(s, e, sh.best, sh.e) {
+		return
+	}
+	sh.best, sh.e = s, e
+	sh.bits.Store(math.Float64bits(e))
+}
+
+// better returns the best State of sh and its energy if it is better than s, whose energy is e, or else nil.
+func (sh *Shared) better(s State, e float64) (State, float64) {
+	if !(sh.energy() <= e) {
+		return nil, 0
+	}
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	if sh.best == nil || !less(sh.best, sh.e, s, e) {
+		return nil, 0
+	}
+	return keep(sh.best), sh.e
+}
+
+// pull makes the best State of the Shared, if any, the best State of the search if it is better,
+// and reports whether it did.
+func (a *annealer) pull() bool {
+	if a.shared == nil {
+		return false
+	}
+	s, e := a.shared.better(a.best, a.ebest)
+	return s != nil && a.promote(s, e)
+}
+
+// exchange moves the run c to the best State of the Shared if it is better than the best State of the search.
+func (a *annealer) exchange(c *chain) {
+	if !a.pull() {
+		return
+	}
+	s := keep(a.best)
+	notify(c.s, s)
+	c.s, c.e, c.f = s, a.ebest, a.ebest
+	if c.diversify {
+		c.f += a.mem.penalty(c.s)
+	}
+	// The run continues from a State that another search found, so its Initializer, if any, is not credited with it.
+	c.init = -1
+}
@@ -0,0 +1,26 @@
+package anneal
+
+import "testing"
+
+func TestSharedRestartsNotCredited(t *testing.T) {
+	// Each restart begins from a State of the Initializer, but before its first exchange
+	// a cooperating search offers a better State to the Shared, which the restart adopts.
+	sh := NewShared()
+	sh.offer(line(500), 500*500)
+	k := 400
+	far := InitializerFunc(func() (State, error) {
+		sh.offer(line(k), float64(k*k))
+		k -= 100
+		return line(1000), nil
+	})
+	r, err := Run(line(1000), WithIterations(100), WithTemperatures(1e-3, 1e-6), WithShared(sh, 10), WithRestarts(3, 0), WithInitializers(far))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Energy > 200*200 {
+		t.Fatalf("Energy = %v, want at most that of the last State offered", r.Energy)
+	}
+	if st := r.Initializers[0]; st != (InitializerStats{}) {
+		t.Errorf("Initializer credited with %d runs and %d wins, want none", st.Runs, st.Wins)
+	}
+}
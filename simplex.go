@@ -0,0 +1,156 @@
+package anneal
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+)
+
+// A SimplexMove is a set of kinds of moves on the probability simplex.
+type SimplexMove int
+
+const (
+	// SimplexTransfer moves mass from one coordinate to another. The amount has standard deviation Step * sqrt(T)
+	// and is reflected as necessary to keep both coordinates nonnegative, so the pair's sum is unchanged.
+	SimplexTransfer SimplexMove = 1 << iota
+
+	// SimplexDirichlet redraws every coordinate from a Dirichlet distribution centered near the current point
+	// with concentration 1 / (Step^2 * T), so that each coordinate moves by about Step * sqrt(T)
+	// and coordinates at zero can become positive.
+	SimplexDirichlet
+
+	SimplexAll = SimplexTransfer | SimplexDirichlet
+)
+
+// A SimplexProblem describes the minimization of a function of a probability distribution
+// over a finite set: a vector of nonnegative numbers summing to 1, such as mixture weights or traffic splits.
+type SimplexProblem struct {
+	Func  func(w []float64) float64 // objective function; must not retain or modify w
+	Step  float64                   // proposal scale, which must be positive
+	Moves SimplexMove               // kinds of moves to choose among uniformly; zero means SimplexAll
+}
+
+// NewState returns a SimplexState of p holding w, which must be nonnegative and sum to 1. It does not copy w.
+func (p *SimplexProblem) NewState(w []float64) *SimplexState {
+	return &SimplexState{W: w, p: p}
+}
+
+// Uniform returns a SimplexState of p holding the uniform distribution on n elements.
+func (p *SimplexProblem) Uniform(n int) *SimplexState {
+	w := make([]float64, n)
+	for i := range w {
+		w[i] = 1 / float64(n)
+	}
+	return p.NewState(w)
+}
+
+// A SimplexState is a point in the search space of a SimplexProblem.
+// Its energy is the value of the objective function.
+// Each neighbor is produced by a move chosen uniformly from the kinds the problem allows,
+// with a size that shrinks with the annealing temperature T, but not below 1e-6 Step. Every move yields a nonnegative vector
+// with the same sum, up to rounding, so the constraint never needs repair.
+// SimplexState implements Tempered, Scaler by multiplying the size of its moves by the factor, which its neighbors inherit,
+// Seeded, and Recycler by drawing the storage of its neighbors from a pool.
+type SimplexState struct {
+	W     []float64
+	p     *SimplexProblem
+	scale float64 // factor set by Scale, or 0 for 1
+	rand  source
+}
+
+// Energy returns the value of the objective function at s.W.
+func (s *SimplexState) Energy() float64 { return s.p.Func(s.W) }
+
+// Neighbor returns a neighbor of s with the step scale evaluated at T = 1.
+func (s *SimplexState) Neighbor() State { return s.NeighborT(1) }
+
+// NeighborT returns a neighbor of s with the step scale evaluated at temperature T.
+func (s *SimplexState) NeighborT(T float64) State {
+	n := s.CloneInto(simplexStates.Get().(*SimplexState))
+	w := n.W
+	if len(w) < 2 {
+		return n
+	}
+	// Floor the scale of the moves as VectorState does, so that the concentration of a Dirichlet move stays finite at T = 0.
+	step := s.p.Step * max(math.Sqrt(T), minStep)
+	if s.scale != 0 {
+		step *= s.scale
+	}
+	kinds := s.p.kinds()
+	switch kinds[s.rand.Intn(len(kinds))] {
+	case SimplexTransfer:
+		i, j := s.rand.Intn(len(w)), s.rand.Intn(len(w)-1)
+		if j >= i {
+			j++
+		}
+		if sum := w[i] + w[j]; sum > 0 {
+			w[i] = reflect(w[i]+step*s.rand.NormFloat64(), 0, sum)
+			w[j] = sum - w[i]
+		}
+	case SimplexDirichlet:
+		k := 1 / (step * step)
+		var sum float64
+		for i, wi := range w {
+			w[i] = gamma(k*wi+1, s.rand)
+			sum += w[i]
+		}
+		for i := range w {
+			w[i] /= sum
+		}
+	}
+	return n
+}
+
+// Release returns the storage of s to the pool from which NeighborT draws.
+func (s *SimplexState) Release() { simplexStates.Put(s) }
+
+// simplexStates holds released SimplexStates for reuse.
+var simplexStates = sync.Pool{New: func() any { return new(SimplexState) }}
+
+// Scale sets the factor by which to multiply the size of the moves of s and its neighbors.
+func (s *SimplexState) Scale(f float64) { s.scale = f }
+
+// UseRand sets the source of randomness of s and its neighbors.
+func (s *SimplexState) UseRand(r *rand.Rand) { s.rand.r = r }
+
+// CloneInto copies s into dst, reusing the storage of dst.W if it is large enough, and returns dst.
+// If dst is nil, it allocates a new SimplexState. NeighborT uses it to fill released States;
+// programs that manage their own pools of States can use it likewise.
+func (s *SimplexState) CloneInto(dst *SimplexState) *SimplexState {
+	if dst == nil {
+		dst = new(SimplexState)
+	}
+	dst.W = append(dst.W[:0], s.W...)
+	dst.p, dst.scale, dst.rand = s.p, s.scale, s.rand
+	return dst
+}
+
+// kinds returns the individual kinds of moves that p allows.
+func (p *SimplexProblem) kinds() []SimplexMove {
+	switch p.Moves & SimplexAll {
+	case SimplexTransfer:
+		return []SimplexMove{SimplexTransfer}
+	case SimplexDirichlet:
+		return []SimplexMove{SimplexDirichlet}
+	}
+	return []SimplexMove{SimplexTransfer, SimplexDirichlet}
+}
+
+// gamma returns a random number from the gamma distribution with shape a >= 1 and scale 1,
+// drawn from src using the method of Marsaglia and Tsang.
+func gamma(a float64, src source) float64 {
+	d := a - 1.0/3
+	c := 1 / math.Sqrt(9*d)
+	for {
+		x := src.NormFloat64()
+		v := 1 + c*x
+		if v <= 0 {
+			continue
+		}
+		v = v * v * v
+		u := src.Float64()
+		if u < 1-0.0331*x*x*x*x || math.Log(u) < 0.5*x*x+d*(1-v+math.Log(v)) {
+			return d * v
+		}
+	}
+}
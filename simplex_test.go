@@ -0,0 +1,55 @@
+package anneal
+
+import (
+	"math"
+	"testing"
+)
+
+func benchSimplex(moves SimplexMove) *SimplexState {
+	p := &SimplexProblem{Func: func(w []float64) float64 { return w[0] }, Step: 0.1, Moves: moves}
+	return p.Uniform(1000)
+}
+
+func BenchmarkSimplexNeighbor(b *testing.B) {
+	for _, bb := range []struct {
+		name  string
+		moves SimplexMove
+	}{
+		{"Transfer", SimplexTransfer},
+		{"Dirichlet", SimplexDirichlet},
+	} {
+		b.Run(bb.name, func(b *testing.B) {
+			s := benchSimplex(bb.moves)
+			b.ReportAllocs()
+			for b.Loop() {
+				s.Neighbor().(Recycler).Release()
+			}
+		})
+	}
+}
+
+func BenchmarkSimplexCloneInto(b *testing.B) {
+	s := benchSimplex(SimplexAll)
+	dst := s.CloneInto(nil)
+	b.ReportAllocs()
+	for b.Loop() {
+		s.CloneInto(dst)
+	}
+}
+
+func TestSimplexNeighborZeroTemperature(t *testing.T) {
+	for _, moves := range []SimplexMove{SimplexTransfer, SimplexDirichlet} {
+		s := benchSimplex(moves)
+		n := s.NeighborT(0).(*SimplexState)
+		var sum float64
+		for _, w := range n.W {
+			if !(w >= 0) {
+				t.Fatalf("moves %d: neighbor at T = 0 has weight %v", moves, w)
+			}
+			sum += w
+		}
+		if math.Abs(sum-1) > 1e-9 {
+			t.Errorf("moves %d: neighbor at T = 0 has weights summing to %v", moves, sum)
+		}
+	}
+}
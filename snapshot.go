@@ -0,0 +1,73 @@
+package anneal
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+)
+
+// A Snapshotter is a State that can serialize itself,
+// so that facilities that record or persist States can store it.
+type Snapshotter interface {
+	State
+	encoding.BinaryMarshaler
+	encoding.BinaryUnmarshaler
+}
+
+// A Codec converts States to and from bytes.
+// Facilities that record or persist States accept a Codec; a nil Codec means BinaryCodec.
+type Codec interface {
+	// Encode returns an encoding of s.
+	Encode(s State) ([]byte, error)
+
+	// Decode decodes data, as returned by Encode, into s, which must be a pointer.
+	Decode(data []byte, s State) error
+}
+
+// ErrNotSnapshotter is returned by BinaryCodec for States that do not implement Snapshotter.
+var ErrNotSnapshotter = errors.New("anneal: State does not implement Snapshotter")
+
+// BinaryCodec encodes States that implement Snapshotter using their own methods.
+type BinaryCodec struct{}
+
+func (BinaryCodec) Encode(s State) ([]byte, error) {
+	sn, ok := s.(Snapshotter)
+	if !ok {
+		return nil, ErrNotSnapshotter
+	}
+	return sn.MarshalBinary()
+}
+
+func (BinaryCodec) Decode(data []byte, s State) error {
+	sn, ok := s.(Snapshotter)
+	if !ok {
+		return ErrNotSnapshotter
+	}
+	return sn.UnmarshalBinary(data)
+}
+
+// GobCodec encodes States using encoding/gob.
+// Only exported fields are encoded, and gob defers to a State's MarshalBinary method if it has one,
+// so a Snapshotter must not implement MarshalBinary by calling GobCodec on itself.
+type GobCodec struct{}
+
+func (GobCodec) Encode(s State) ([]byte, error) {
+	var b bytes.Buffer
+	if err := gob.NewEncoder(&b).Encode(s); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+func (GobCodec) Decode(data []byte, s State) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(s)
+}
+
+// JSONCodec encodes States using encoding/json. Only exported fields are encoded.
+type JSONCodec struct{}
+
+func (JSONCodec) Encode(s State) ([]byte, error) { return json.Marshal(s) }
+
+func (JSONCodec) Decode(data []byte, s State) error { return json.Unmarshal(data, s) }
@@ -0,0 +1,489 @@
+/*
+Package statetest checks implementations of anneal.State for common violations of the State contract,
+in the manner of testing/fstest. A State that breaks the contract rarely fails outright;
+more often the search merely converges poorly, which is hard to trace to its cause.
+
+	func TestTour(t *testing.T) {
+		statetest.Check(t, NewTour(cities))
+	}
+
+Check proposes neighbors of a State and walks a chain of them as a search would, and it reports
+  - energies that are NaN or infinite, or that change when evaluated again;
+  - neighbors that are nil or the State itself;
+  - a Neighbor that returns equal States on every call, as when it seeds its random source anew on each call;
+  - States that change when neighbors are proposed from them or from States reached from them,
+    which reveals memory shared with their neighbors, such as a slice copied by assignment rather than element by element;
+  - inconsistencies in the optional interfaces of package anneal that the States implement:
+    a RecomputeEnergy that disagrees with Energy, equal States with different hashes, a snapshot that does not
+    restore the State, and operators or neighborhoods that produce States violating the contract.
+
+States are compared by the values of all memory reachable from them, except for random sources such as *rand.Rand,
+so a State that caches a value lazily should compute it in Energy rather than in Neighbor.
+A State whose neighborhood contains a single State fails the check for distinct neighbors.
+*/
+package statetest
+
+import (
+	"bytes"
+	"fmt"
+	"hash/fnv"
+	"maps"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/dkmccandless/anneal"
+)
+
+// DefaultSteps is the number of States in the walk of a Config whose Steps is zero.
+const DefaultSteps = 64
+
+// A Config adjusts the checks that Check performs. The zero Config suits States with deterministic, finite energies.
+type Config struct {
+	Steps      int  // number of States adopted in the walk; 0 means DefaultSteps
+	Noisy      bool // energies are random estimates, so repeated evaluations need not agree
+	Infeasible bool // an energy of +Inf marks an infeasible State, as with anneal.InfeasibleInf
+}
+
+// Check checks s and the States reached from it with the zero Config.
+func Check(t testing.TB, s anneal.State) {
+	t.Helper()
+	Config{}.Check(t, s)
+}
+
+const (
+	distinct = 16   // number of neighbors of the input State compared for distinctness
+	fanout   = 4    // number of neighbors proposed from each State of the walk, of which the last is adopted
+	maxScan  = 1000 // largest number of States of a Neighborhood checked
+	tol      = 1e-9 // relative difference between Energy and RecomputeEnergy above which they disagree
+)
+
+// Check checks s and the States reached from it, reporting each violation with t.Errorf.
+// It stops at the first State found to violate the contract, since the States reached from it are suspect.
+func (c Config) Check(t testing.TB, s anneal.State) {
+	t.Helper()
+	ck := &checker{t: t, c: c, hashes: make(map[uint64]uint64)}
+	in, ok := ck.state("the input State", s)
+	if !ok {
+		return
+	}
+	scale := math.Abs(in.e)
+	if scale == 0 || math.IsInf(scale, 0) {
+		scale = 1
+	}
+	ck.temps = []float64{scale, 1e-2 * scale, 1e-4 * scale}
+
+	seen := make(map[uint64]bool)
+	for k := range distinct {
+		n, ok := ck.proposal(in, k)
+		if !ok {
+			return
+		}
+		seen[n.print] = true
+	}
+	if len(seen) == 1 {
+		if seen[in.print] {
+			ck.errorf("%d neighbors of the input State are all equal to it", distinct)
+		} else {
+			ck.errorf("%d neighbors of the input State are all equal; Neighbor may be seeding its random source anew on each call", distinct)
+		}
+		return
+	}
+	if !ck.snapshot(in) || !ck.neighborhood(in) {
+		return
+	}
+
+	steps := c.Steps
+	if steps <= 0 {
+		steps = DefaultSteps
+	}
+	walk := []visited{in}
+	cur := in
+	var notified bool
+	for i := range steps {
+		var next visited
+		for k := range fanout {
+			if next, ok = ck.proposal(cur, i*fanout+k); !ok {
+				return
+			}
+		}
+		if n, ok := next.s.(anneal.AcceptNotifier); ok {
+			n.OnAccept(cur.s, next.s)
+			next.print = fingerprint(next.s)
+			notified = true
+		}
+		next.name = fmt.Sprintf("State %d of the walk", i+1)
+		walk = append(walk, next)
+		cur = next
+	}
+
+	// OnAccept may legitimately hand auxiliary data from one State to the next,
+	// so the States of a walk of AcceptNotifiers need not survive it unchanged.
+	if notified {
+		return
+	}
+	for _, v := range walk {
+		if fingerprint(v.s) != v.print {
+			ck.errorf("%s changed while States reached from it proposed neighbors; a State must not share memory with its neighbors", v.name)
+			return
+		}
+		if e, err := energy(v.s); err == nil && !c.Noisy && !same(e, v.e) {
+			ck.errorf("%s had energy %v, but %v after States reached from it proposed neighbors", v.name, v.e, e)
+			return
+		}
+	}
+}
+
+// A checker performs the checks of a Config.
+type checker struct {
+	t      testing.TB
+	c      Config
+	failed bool
+	temps  []float64         // temperatures at which to propose neighbors of Tempered States
+	moves  int               // number of operators of Mover States
+	hashes map[uint64]uint64 // hash of each Hasher State, by fingerprint
+}
+
+// A visited State has been checked. It records the fingerprint and energy that the State had at the time.
+type visited struct {
+	s     anneal.State
+	name  string
+	print uint64
+	e     float64
+}
+
+func (ck *checker) errorf(format string, args ...any) {
+	ck.t.Helper()
+	ck.t.Errorf("statetest: "+format, args...)
+	ck.failed = true
+}
+
+// state checks the State s, described by name, and reports whether it satisfies the contract.
+func (ck *checker) state(name string, s anneal.State) (visited, bool) {
+	ck.t.Helper()
+	if s == nil {
+		ck.errorf("%s is nil", name)
+		return visited{}, false
+	}
+	e, err := energy(s)
+	if err != nil {
+		ck.errorf("%s: %v", name, err)
+		return visited{}, false
+	}
+	if math.IsNaN(e) || math.IsInf(e, -1) || math.IsInf(e, 1) && !ck.c.Infeasible {
+		ck.errorf("%s has energy %v", name, e)
+		return visited{}, false
+	}
+	v := visited{s: s, name: name, print: fingerprint(s), e: e}
+	if !ck.c.Noisy {
+		if e2, err := energy(s); err != nil || !same(e, e2) {
+			ck.errorf("%s has energy %v, then %v (error %v) when evaluated again", name, e, e2, err)
+			return v, false
+		}
+		if r, ok := s.(anneal.Recomputer); ok {
+			if re := r.RecomputeEnergy(); !same(e, re) && !(math.Abs(e-re) <= tol*math.Max(1, math.Abs(re))) {
+				ck.errorf("%s has energy %v, but RecomputeEnergy returns %v", name, e, re)
+				return v, false
+			}
+		}
+	}
+	if fingerprint(s) != v.print {
+		ck.errorf("%s changed when its energy was evaluated", name)
+		return v, false
+	}
+	if h, ok := s.(anneal.Hasher); ok {
+		hash := h.Hash()
+		if hash2 := h.Hash(); hash2 != hash {
+			ck.errorf("%s has hash %#x, then %#x", name, hash, hash2)
+			return v, false
+		}
+		if prev, ok := ck.hashes[v.print]; ok && prev != hash {
+			ck.errorf("%s has hash %#x, but an equal State has hash %#x", name, hash, prev)
+			return v, false
+		}
+		ck.hashes[v.print] = hash
+	}
+	if m, ok := s.(anneal.Mover); ok {
+		switch n := m.Moves(); {
+		case n <= 0:
+			ck.errorf("%s has %d operators", name, n)
+			return v, false
+		case ck.moves == 0:
+			ck.moves = n
+		case n != ck.moves:
+			ck.errorf("%s has %d operators, but the input State has %d", name, n, ck.moves)
+			return v, false
+		}
+	}
+	return v, true
+}
+
+// proposal checks the k-th neighbor proposed from v and its effect on v.
+func (ck *checker) proposal(v visited, k int) (visited, bool) {
+	ck.t.Helper()
+	n, how, err := ck.propose(v.s, k)
+	name := how + " of " + v.name
+	switch {
+	case err != nil:
+		ck.errorf("%s: %v", name, err)
+		return visited{}, false
+	case n != nil && identical(n, v.s):
+		ck.errorf("%s is the State itself", name)
+		return visited{}, false
+	case !ck.unchanged(v, how):
+		return visited{}, false
+	}
+	return ck.state(name, n)
+}
+
+// propose returns the k-th neighbor proposed from s, cycling through the operators of a Mover
+// and the temperatures of a Tempered State, and describes how it was proposed.
+func (ck *checker) propose(s anneal.State, k int) (anneal.State, string, error) {
+	switch s := s.(type) {
+	case anneal.Mover:
+		op := k % ck.moves
+		return s.Move(op), fmt.Sprintf("Move(%d)", op), nil
+	case anneal.Tempered:
+		T := ck.temps[k%len(ck.temps)]
+		return s.NeighborT(T), fmt.Sprintf("NeighborT(%v)", T), nil
+	case anneal.FallibleNeighbor:
+		n, err := s.NeighborErr()
+		return n, "NeighborErr", err
+	}
+	return s.Neighbor(), "Neighbor", nil
+}
+
+// unchanged checks that v is as it was when visited, after calling the method described by during.
+func (ck *checker) unchanged(v visited, during string) bool {
+	ck.t.Helper()
+	if fingerprint(v.s) != v.print {
+		ck.errorf("%s changed during %s; a State must not share memory with its neighbors", v.name, during)
+		return false
+	}
+	return true
+}
+
+// snapshot checks that a Snapshotter restores v from its snapshot.
+func (ck *checker) snapshot(v visited) bool {
+	ck.t.Helper()
+	s, ok := v.s.(anneal.Snapshotter)
+	if !ok {
+		return true
+	}
+	data, err := s.MarshalBinary()
+	if err != nil {
+		ck.errorf("%s: MarshalBinary: %v", v.name, err)
+		return false
+	}
+	n, ok := ck.proposal(v, 0)
+	if !ok {
+		return false
+	}
+	dst, ok := n.s.(anneal.Snapshotter)
+	if !ok {
+		return true
+	}
+	name := "a neighbor of " + v.name + " restored from its snapshot"
+	if err := dst.UnmarshalBinary(data); err != nil {
+		ck.errorf("%s: UnmarshalBinary: %v", name, err)
+		return false
+	}
+	if !ck.unchanged(v, "UnmarshalBinary of its snapshot") {
+		return false
+	}
+	if e, err := energy(dst); err == nil && !ck.c.Noisy && !same(e, v.e) {
+		ck.errorf("%s has energy %v, want %v", name, e, v.e)
+		return false
+	}
+	if data2, err := dst.MarshalBinary(); err != nil || !bytes.Equal(data, data2) {
+		ck.errorf("%s has a different snapshot (error %v)", name, err)
+		return false
+	}
+	return true
+}
+
+// neighborhood checks the States in the Neighborhood of an Enumerator v, up to maxScan of them.
+func (ck *checker) neighborhood(v visited) bool {
+	ck.t.Helper()
+	en, ok := v.s.(anneal.Enumerator)
+	if !ok {
+		return true
+	}
+	var k int
+	for n := range en.Neighborhood() {
+		name := fmt.Sprintf("State %d of the Neighborhood of %s", k, v.name)
+		if n != nil && identical(n, v.s) {
+			ck.errorf("%s is the State itself", name)
+			return false
+		}
+		if _, ok := ck.state(name, n); !ok {
+			return false
+		}
+		if k++; k == maxScan {
+			break
+		}
+	}
+	return ck.unchanged(v, "Neighborhood")
+}
+
+// energy returns the energy of s as a search evaluates it.
+func energy(s anneal.State) (float64, error) {
+	switch s := s.(type) {
+	case anneal.FallibleEnergy:
+		e, err := s.EnergyErr()
+		if err != nil {
+			return 0, fmt.Errorf("EnergyErr: %w", err)
+		}
+		return e, nil
+	case anneal.IntEnergy:
+		return float64(s.EnergyInt()), nil
+	}
+	return s.Energy(), nil
+}
+
+// same reports whether x and y are the same energy.
+func same(x, y float64) bool { return x == y || math.IsNaN(x) && math.IsNaN(y) }
+
+// identical reports whether a and b are the same value of a reference type, and so share all their memory.
+func identical(a, b anneal.State) bool {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	if va.Type() != vb.Type() {
+		return false
+	}
+	switch va.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Chan, reflect.UnsafePointer:
+		return va.Pointer() == vb.Pointer()
+	case reflect.Slice:
+		return va.Pointer() == vb.Pointer() && va.Len() == vb.Len() && va.Len() > 0
+	}
+	return false
+}
+
+// Random sources change whenever they are drawn from, and are exempt from comparison.
+type (
+	source   interface{ Int63() int64 }
+	source64 interface{ Uint64() uint64 }
+)
+
+var sourceTypes = []reflect.Type{reflect.TypeFor[source](), reflect.TypeFor[source64]()}
+
+// random reports whether values of type t are random sources.
+func random(t reflect.Type) bool {
+	for _, src := range sourceTypes {
+		if t.Implements(src) || t.Kind() != reflect.Interface && reflect.PointerTo(t).Implements(src) {
+			return true
+		}
+	}
+	return false
+}
+
+// fingerprint returns a hash of the values of all memory reachable from s, other than random sources.
+func fingerprint(s anneal.State) uint64 {
+	return newPrinter().value(reflect.ValueOf(s))
+}
+
+// A printer computes a fingerprint.
+type printer struct {
+	seen map[uintptr]uint64 // index of each pointer visited, to terminate cycles
+	buf  []byte
+}
+
+func newPrinter() *printer { return &printer{seen: make(map[uintptr]uint64)} }
+
+func (p *printer) word(x uint64) {
+	p.buf = append(p.buf, byte(x), byte(x>>8), byte(x>>16), byte(x>>24), byte(x>>32), byte(x>>40), byte(x>>48), byte(x>>56))
+}
+
+// value returns the fingerprint of v.
+func (p *printer) value(v reflect.Value) uint64 {
+	p.walk(v)
+	h := fnv.New64a()
+	h.Write(p.buf)
+	return h.Sum64()
+}
+
+// walk appends a description of v to p.buf.
+func (p *printer) walk(v reflect.Value) {
+	if !v.IsValid() {
+		p.word(0)
+		return
+	}
+	if random(v.Type()) {
+		return
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			p.word(1)
+		} else {
+			p.word(0)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		p.word(uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		p.word(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		p.word(math.Float64bits(v.Float()))
+	case reflect.Complex64, reflect.Complex128:
+		p.word(math.Float64bits(real(v.Complex())))
+		p.word(math.Float64bits(imag(v.Complex())))
+	case reflect.String:
+		p.word(uint64(v.Len()))
+		p.buf = append(p.buf, v.String()...)
+	case reflect.Array:
+		for i := range v.Len() {
+			p.walk(v.Index(i))
+		}
+	case reflect.Slice:
+		if v.IsNil() {
+			p.word(0)
+			return
+		}
+		p.word(uint64(v.Len()) + 1)
+		for i := range v.Len() {
+			p.walk(v.Index(i))
+		}
+	case reflect.Map:
+		if v.IsNil() {
+			p.word(0)
+			return
+		}
+		// Combine the fingerprints of the entries independently of the order of iteration.
+		// Each entry starts from the pointers visited so far, so that a cycle through the map terminates,
+		// but not from those of the other entries, which would make it depend on the order.
+		var sum uint64
+		for it := v.MapRange(); it.Next(); {
+			q := &printer{seen: maps.Clone(p.seen)}
+			q.walk(it.Key())
+			sum += q.value(it.Value())
+		}
+		p.word(uint64(v.Len()) + 1)
+		p.word(sum)
+	case reflect.Pointer:
+		if v.IsNil() {
+			p.word(0)
+			return
+		}
+		if k, ok := p.seen[v.Pointer()]; ok {
+			p.word(1<<32 | k)
+			return
+		}
+		p.seen[v.Pointer()] = uint64(len(p.seen))
+		p.word(1)
+		p.walk(v.Elem())
+	case reflect.Interface:
+		if v.IsNil() {
+			p.word(0)
+			return
+		}
+		p.word(1)
+		p.buf = append(p.buf, v.Elem().Type().String()...)
+		p.walk(v.Elem())
+	case reflect.Struct:
+		for i := range v.NumField() {
+			p.walk(v.Field(i))
+		}
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		p.word(uint64(v.Pointer()))
+	}
+}
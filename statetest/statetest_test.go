@@ -0,0 +1,206 @@
+package statetest_test
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/dkmccandless/anneal"
+	"github.com/dkmccandless/anneal/qubo"
+	"github.com/dkmccandless/anneal/statetest"
+	"github.com/dkmccandless/anneal/tsp"
+)
+
+// A recorder is a testing.TB that records the errors reported to it.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+// check runs Check on s and returns the errors it reports.
+func check(s anneal.State) []string {
+	r := new(recorder)
+	statetest.Check(r, s)
+	return r.errors
+}
+
+// A vec is a vector of integers whose neighbors differ in one randomly chosen element.
+// Its variants below each break the State contract in one way.
+type vec struct{ x []int }
+
+func (v *vec) Energy() float64 {
+	var e float64
+	for _, x := range v.x {
+		e += float64(x * x)
+	}
+	return e
+}
+
+func (v *vec) Neighbor() anneal.State { return &vec{v.move(rand.Intn)} }
+
+// move returns a copy of v.x changed in an element chosen by intn.
+func (v *vec) move(intn func(int) int) []int {
+	x := append([]int(nil), v.x...)
+	x[intn(len(x))] += 2*intn(2) - 1
+	return x
+}
+
+// An aliased vec shares its elements with its neighbors.
+type aliased struct{ vec }
+
+func (a *aliased) Neighbor() anneal.State {
+	n := &aliased{vec{a.x}}
+	n.x[rand.Intn(len(n.x))]++
+	return n
+}
+
+// A nonFinite vec has the given energy at its origin.
+type nonFinite struct {
+	vec
+	e float64
+}
+
+func (v *nonFinite) Energy() float64 {
+	if e := v.vec.Energy(); e != 0 {
+		return e
+	}
+	return v.e
+}
+
+func (v *nonFinite) Neighbor() anneal.State { return &nonFinite{vec{v.move(rand.Intn)}, v.e} }
+
+// A reseeded vec proposes its neighbors from a source seeded anew on each call.
+type reseeded struct{ vec }
+
+func (v *reseeded) Neighbor() anneal.State {
+	r := rand.New(rand.NewSource(1))
+	return &reseeded{vec{v.move(r.Intn)}}
+}
+
+// A badHash vec has different hashes for equal States.
+type badHash struct{ vec }
+
+func (v *badHash) Neighbor() anneal.State { return &badHash{vec{v.move(rand.Intn)}} }
+
+func (v *badHash) Hash() uint64 { return rand.Uint64() }
+
+// A badRecompute vec recomputes a different energy than it reports.
+type badRecompute struct{ vec }
+
+func (v *badRecompute) Neighbor() anneal.State { return &badRecompute{vec{v.move(rand.Intn)}} }
+
+func (v *badRecompute) RecomputeEnergy() float64 { return v.Energy() + 1 }
+
+// A badSnapshot vec does not restore itself from its snapshot.
+type badSnapshot struct{ vec }
+
+func (v *badSnapshot) Neighbor() anneal.State { return &badSnapshot{vec{v.move(rand.Intn)}} }
+
+func (v *badSnapshot) MarshalBinary() ([]byte, error) {
+	var b []byte
+	for _, x := range v.x {
+		b = binary.AppendVarint(b, int64(x))
+	}
+	return b, nil
+}
+
+func (v *badSnapshot) UnmarshalBinary(data []byte) error { return nil }
+
+// A linked vec refers to itself through a map.
+type linked struct {
+	vec
+	links map[string]*linked
+}
+
+func newLinked(x []int) *linked {
+	l := &linked{vec: vec{x}}
+	l.links = map[string]*linked{"self": l}
+	return l
+}
+
+func (l *linked) Neighbor() anneal.State { return newLinked(l.move(rand.Intn)) }
+
+func TestBroken(t *testing.T) {
+	x := func() []int { return []int{3, -1, 4, 1, -5} }
+	for _, tt := range []struct {
+		name string
+		s    anneal.State
+		want string
+	}{
+		{"aliased", &aliased{vec{x()}}, "must not share memory"},
+		{"NaN", &nonFinite{vec{[]int{1}}, math.NaN()}, "energy NaN"},
+		{"Inf", &nonFinite{vec{[]int{1}}, math.Inf(1)}, "energy +Inf"},
+		{"reseeded", &reseeded{vec{x()}}, "seeding its random source"},
+		{"Hasher", &badHash{vec{x()}}, "hash"},
+		{"Recomputer", &badRecompute{vec{x()}}, "RecomputeEnergy"},
+		{"Snapshotter", &badSnapshot{vec{x()}}, "restored from its snapshot"},
+	} {
+		errs := check(tt.s)
+		if len(errs) == 0 {
+			t.Errorf("%s: Check reported no errors", tt.name)
+			continue
+		}
+		if !strings.Contains(errs[0], tt.want) {
+			t.Errorf("%s: Check reported %q, want an error mentioning %q", tt.name, errs[0], tt.want)
+		}
+	}
+}
+
+const instance = `NAME: square
+TYPE: TSP
+DIMENSION: 8
+EDGE_WEIGHT_TYPE: EUC_2D
+NODE_COORD_SECTION
+1 0 0
+2 10 0
+3 20 0
+4 20 10
+5 20 20
+6 10 20
+7 0 20
+8 0 10
+EOF
+`
+
+func TestStates(t *testing.T) {
+	in, err := tsp.Parse(strings.NewReader(instance))
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := qubo.New(6, []qubo.Term{
+		{I: 0, J: 0, V: -1},
+		{I: 0, J: 1, V: 2},
+		{I: 1, J: 2, V: -1},
+		{I: 2, J: 5, V: 3},
+		{I: 3, J: 4, V: -2},
+		{I: 4, J: 4, V: 1},
+	})
+	v := &anneal.VectorProblem{
+		Func:  func(x []float64) float64 { return x[0]*x[0] + x[1]*x[1] },
+		Lower: []float64{-1, -1},
+		Upper: []float64{1, 1},
+		Step:  0.1,
+	}
+	for _, tt := range []struct {
+		name string
+		s    anneal.State
+	}{
+		{"tsp.Tour", in.RandomTour()},
+		{"qubo.State", q.Random()},
+		{"VectorState", v.NewState([]float64{0.5, -0.5})},
+		{"map cycle", newLinked([]int{3, -1, 4})},
+	} {
+		for _, err := range check(tt.s) {
+			t.Errorf("%s: %s", tt.name, err)
+		}
+	}
+}
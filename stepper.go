@@ -0,0 +1,258 @@
+package anneal
+
+import (
+	"fmt"
+	"iter"
+	"maps"
+)
+
+// An Annealer performs the search that Run performs one step at a time,
+// so that the caller can interleave it with other work such as rendering or game ticks.
+//
+//	an, err := anneal.New(s, sch)
+//	if err != nil {
+//		return err
+//	}
+//	defer an.Close()
+//	for an.Step() {
+//		draw(an.Best(), an.Temperature())
+//	}
+//	return an.Err()
+//
+// An Annealer is not safe for concurrent use.
+type Annealer struct {
+	a        *annealer
+	sch      *Schedule
+	c        *chain   // current run, or nil
+	last     *chain   // most recent run
+	d        *descent // finishing descent, or nil
+	restarts int      // number of restarts begun
+	usage    usageSample
+	span     Span // span of the search, or nil
+	err      error
+}
+
+// New returns an Annealer that searches from s as Run does.
+// It returns an error if the Schedule is not valid or the energy of s cannot be computed.
+// The caller must call Close when finished with the Annealer.
+func New(s State, opts ...Option) (*Annealer, error) {
+	sch := configure(opts)
+	if err := sch.Validate(); err != nil {
+		return nil, err
+	}
+	e, err := meanEnergy(s, sch.Samples)
+	if err == nil && !admitInput(e, sch.NonFinite) {
+		err = &NonFiniteError{e}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("anneal: input State: %w", err)
+	}
+	an := &Annealer{sch: sch, usage: sampleUsage()}
+	an.a = newAnnealer(s, e, sch)
+	an.c = an.a.begin(s, e, false)
+	an.last = an.c
+	an.startSpan()
+	return an, nil
+}
+
+// Step advances the search and reports whether it continues.
+// Each step performs one iteration, or one batch of iterations if Schedule.Workers is greater than 1;
+// a systematic scan is performed in a single step. A run whose States are so cheap that the cost of stepping
+// would dominate, and that uses no options such as Workers, Audit, Keep, or Acceptor that require per-iteration hooks,
+// switches after its first few hundred iterations to steps of several thousand iterations each,
+// stopping short to deliver each progress report.
+// Step returns false once the search is complete or has stopped with an error, which Err returns.
+func (an *Annealer) Step() bool {
+	if an.step() {
+		return true
+	}
+	if err := an.a.flush(); err != nil && an.err == nil {
+		an.err = err
+	}
+	an.endSpan()
+	return false
+}
+
+// step advances the search as described for Step.
+func (an *Annealer) step() bool {
+	if an.err != nil {
+		return false
+	}
+	a := an.a
+	switch {
+	case an.c != nil:
+		more, err := a.step(an.c)
+		if more && err == nil {
+			return true
+		}
+		a.end(an.c)
+		if a.wins > an.c.wins {
+			an.event("best", Attribute{"run", a.runs - 1}, Attribute{"energy", a.ebest}, Attribute{"temperature", a.tbest})
+		}
+		an.c = nil
+		if err != nil {
+			an.err = err
+			return false
+		}
+		an.err = an.next()
+		return an.err == nil && (an.c != nil || an.d != nil)
+	case an.d != nil:
+		more, err := a.descend(an.d)
+		if more && err == nil {
+			return true
+		}
+		a.cur = an.d.s
+		an.d = nil
+		an.err = err
+	}
+	return false
+}
+
+// next begins the phase of the search that follows a run: a restart, the finishing descent, or none.
+func (an *Annealer) next() error {
+	a := an.a
+	if an.restarts < an.sch.Restarts && !a.stopped() {
+		an.restarts++
+		if a.inits != nil {
+			var err error
+			if an.c, err = a.restart(a.mem != nil); err != nil {
+				return err
+			}
+		} else {
+			a.pull()
+			an.c = a.begin(keep(a.best), a.ebest, a.mem != nil)
+		}
+		an.last = an.c
+		attrs := []Attribute{{"run", a.runs - 1}, {"energy", an.c.e}}
+		if an.c.init >= 0 {
+			attrs = append(attrs, Attribute{"initializer", an.c.init})
+		}
+		an.event("restart", attrs...)
+		return nil
+	}
+	if an.sch.Polish > 0 {
+		an.d = a.polish(an.sch.Polish, an.finalTemp())
+		notify(a.cur, an.d.s)
+		an.event("polish", Attribute{"energy", a.ebest})
+	}
+	return nil
+}
+
+// finalTemp returns the temperature at the end of each run.
+func (an *Annealer) finalTemp() float64 {
+	a := an.a
+	if a.duration > 0 {
+		return a.law(float64(a.duration))
+	}
+	return a.temp(a.iter)
+}
+
+// Best returns the best State encountered so far.
+func (an *Annealer) Best() State { return an.a.best }
+
+// Energy returns the energy of the best State encountered so far.
+func (an *Annealer) Energy() float64 { return an.a.ebest }
+
+// Current returns the current State of the search.
+func (an *Annealer) Current() State {
+	switch {
+	case an.c != nil:
+		return an.c.s
+	case an.d != nil:
+		return an.d.s
+	}
+	return an.a.cur
+}
+
+// Temperature returns the temperature of the next step, or the final temperature once the search is complete.
+func (an *Annealer) Temperature() float64 {
+	switch {
+	case an.c != nil:
+		return an.a.temp(an.c.i)
+	case an.d != nil:
+		return an.d.T
+	}
+	return an.finalTemp()
+}
+
+// Progress describes the search after the most recent step.
+// During the finishing descent, Iter and Accepted describe the last run and Energy the current State of the descent.
+func (an *Annealer) Progress() Progress {
+	c := an.last
+	p := an.a.progress(c.i, an.Temperature(), c.e, c.accepted)
+	if an.d != nil {
+		p.Energy = an.d.e
+	}
+	return p
+}
+
+// All returns an iterator over the steps of the search that yields the index of each step and the Progress after it.
+// Breaking out of the loop pauses the search, which a later call to Step or All resumes.
+func (an *Annealer) All() iter.Seq2[int, Progress] {
+	return func(yield func(int, Progress) bool) {
+		for i := 0; an.Step(); i++ {
+			if !yield(i, an.Progress()) {
+				return
+			}
+		}
+	}
+}
+
+// Iterate returns an iterator over the steps of a search from s, as described for Annealer.All.
+// It yields nothing if the Schedule is not valid or the energy of s cannot be computed.
+// Because the iterator cannot report errors, callers that need them should use New and Annealer.All instead.
+func Iterate(s State, opts ...Option) iter.Seq2[int, Progress] {
+	return func(yield func(int, Progress) bool) {
+		an, err := New(s, opts...)
+		if err != nil {
+			return
+		}
+		defer an.Close()
+		for i, p := range an.All() {
+			if !yield(i, p) {
+				return
+			}
+		}
+	}
+}
+
+// Err returns the error that stopped the search, if any.
+func (an *Annealer) Err() error { return an.err }
+
+// Result returns a Result describing the search so far.
+func (an *Annealer) Result() Result {
+	a := an.a
+	T := a.tend
+	if an.c != nil {
+		T = a.temp(an.c.i)
+	}
+	var Tu float64
+	if a.scale > 0 {
+		Tu = T / a.scale
+	}
+	return Result{
+		Best:            a.best,
+		Energy:          a.ebest,
+		Certificate:     a.cert,
+		Drift:           a.drift,
+		Duplicates:      a.duplicates(),
+		Elite:           a.elite(),
+		Evaluations:     a.evals,
+		BestTemperature: a.tbest,
+		Temperature:     Tu,
+		temp:            T,
+		Initializers:    a.initializerStats(),
+		Moves:           append([]MoveStats(nil), a.moves...),
+		History:         a.historyEntries(),
+		Usage:           sampleUsage().since(an.usage, a.peak),
+		RunUsage:        append([]Usage(nil), a.usage...),
+		Labels:          maps.Clone(a.labels),
+	}
+}
+
+// Close releases the resources held by the Annealer. The Annealer must not be stepped afterward.
+// If the search is incomplete, Close ends its span as abandoned; see Span.
+func (an *Annealer) Close() {
+	an.endSpan(Attribute{"abandoned", true})
+	an.a.close()
+}
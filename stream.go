@@ -0,0 +1,89 @@
+package anneal
+
+import (
+	"sync"
+	"time"
+)
+
+// An Improvement reports a new best State found by a search.
+type Improvement struct {
+	State       State         // best State found so far
+	Energy      float64       // energy of State
+	Run         int           // index of the run, counting restarts, in which State was found
+	Evaluations int           // number of energy evaluations performed when State was found
+	Elapsed     time.Duration // time elapsed in the search when State was found, as measured by Schedule.Clock
+}
+
+// AnnealStream anneals s as Run does in a new goroutine and sends on the returned channel each new best State
+// as it is found, beginning with the input State, so that a user interface or server can show intermediate solutions
+// before the search is complete. The channel is closed when the search ends.
+//
+// The search never waits for the receiver: an Improvement not yet received when the next one is found
+// is replaced by it, so the receiver always sees the latest, and the last is received before the channel is closed.
+// Improvements found within a single step of the search, as described for Annealer.Step, are reported as one.
+//
+// Calling cancel stops the search and returns once it has stopped. Cancel may be called more than once,
+// and the caller should call it when it no longer needs the stream, even if the search has ended.
+// Because the channel cannot report errors, callers that need them should use New instead.
+// In particular, if the Schedule is not valid or the energy of s cannot be computed,
+// the channel is closed without sending anything.
+func AnnealStream(s State, opts ...Option) (<-chan Improvement, func()) {
+	ch := make(chan Improvement, 1)
+	stop, done := make(chan struct{}), make(chan struct{})
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() { close(stop) })
+		<-done
+	}
+	go func() {
+		defer close(done)
+		defer close(ch)
+		an, err := New(s, opts...)
+		if err != nil {
+			return
+		}
+		defer an.Close()
+		a := an.a
+		start := a.clock.Now()
+		wins := -1
+		for i, more := 0, true; ; i++ {
+			if a.wins != wins {
+				wins = a.wins
+				offer(ch, Improvement{
+					State:       a.best,
+					Energy:      a.ebest,
+					Run:         max(a.runs-1, 0),
+					Evaluations: a.evals,
+					Elapsed:     a.clock.Now().Sub(start),
+				})
+			}
+			if !more {
+				return
+			}
+			if i%ctxCheck == 0 {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+			}
+			more = an.Step()
+		}
+	}()
+	return ch, cancel
+}
+
+// offer sends imp on ch, whose capacity is 1 and which has a single sender, replacing any value not yet received.
+func offer(ch chan Improvement, imp Improvement) {
+	for {
+		select {
+		case ch <- imp:
+			return
+		default:
+		}
+		select {
+		case <-ch:
+		default:
+		}
+	}
+}
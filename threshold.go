@@ -0,0 +1,106 @@
+package anneal
+
+import "errors"
+
+// A ThresholdAccepting is an Acceptor that implements threshold accepting, the deterministic variant
+// of simulated annealing of Dueck and Scheuer: it adopts every proposal whose energy exceeds the current energy
+// by at most a threshold, and no others. The threshold falls linearly from Start at the beginning
+// of each run to End after Iter iterations, in Levels equal steps, and stays at End thereafter.
+// The temperatures of the Schedule are not used, and no random numbers are drawn.
+//
+//	ta := &anneal.ThresholdAccepting{Iter: n, Levels: 20}
+//	r, err := anneal.Run(s, anneal.WithIterations(n), anneal.WithAcceptor(ta))
+//
+// If Start is zero, it is estimated from the first run: the first ThresholdProbeLen proposals are decided greedily,
+// and Start becomes the mean of the increases in energy that they would have caused,
+// so that the threshold begins on the scale of a typical deteriorating move.
+// A ThresholdAccepting keeps state between decisions, so it must not be shared by concurrent searches.
+type ThresholdAccepting struct {
+	Start  float64 // threshold at the start of each run, in units of energy; zero means estimated
+	End    float64 // threshold after Iter iterations
+	Iter   int     // number of iterations over which the threshold falls, typically Schedule.Iter
+	Levels int     // number of thresholds from Start to End; values less than 1 mean a new threshold every iteration
+
+	probe int     // number of proposals observed while estimating Start
+	sum   float64 // sum of the increases observed while estimating Start
+	ups   int     // number of increases observed while estimating Start
+}
+
+// ThresholdProbeLen is the number of proposals from which ThresholdAccepting estimates its starting threshold.
+const ThresholdProbeLen = 100
+
+// Accept adopts d.Proposed if d.Delta does not exceed the current threshold.
+func (ta *ThresholdAccepting) Accept(d Decision) (bool, error) {
+	if ta.Start == 0 {
+		if d.Delta > 0 {
+			ta.sum += d.Delta
+			ta.ups++
+		}
+		if ta.probe++; ta.probe >= ThresholdProbeLen && ta.ups > 0 {
+			ta.Start = ta.sum / float64(ta.ups)
+		}
+		return d.Delta <= 0, nil
+	}
+	return d.Delta <= ta.Threshold(d.Iter), nil
+}
+
+// Threshold returns the threshold at iteration i of a run.
+func (ta *ThresholdAccepting) Threshold(i int) float64 {
+	if ta.Iter <= 0 || i >= ta.Iter {
+		return ta.End
+	}
+	x := float64(i) / float64(ta.Iter)
+	if ta.Levels > 0 {
+		x = float64(int(x*float64(ta.Levels))) / float64(ta.Levels)
+	}
+	return ta.Start + x*(ta.End-ta.Start)
+}
+
+// A GreatDeluge is an Acceptor that implements the great deluge algorithm of Dueck, in the form
+// of Burke and others that also adopts every proposal that does not increase the energy:
+// it adopts a proposal if its energy does not exceed the current energy or a water level,
+// which begins each run at the energy of the run's starting State plus Margin and falls by Rain every iteration.
+// The comparison with the current energy includes any diversification penalty, as Decision.Delta does,
+// but the water level is compared with the energy of the proposal alone, as are Target and Margin.
+// If Rain is zero, it is chosen so that the level reaches Target after Iter iterations,
+// which needs only an estimate of the energy sought, such as a known lower bound or the result of a previous search.
+// The temperatures of the Schedule are not used, and no random numbers are drawn.
+//
+//	gd := &anneal.GreatDeluge{Target: bound, Iter: n}
+//	r, err := anneal.Run(s, anneal.WithIterations(n), anneal.WithAcceptor(gd))
+//
+// A GreatDeluge keeps state between decisions, so it must not be shared by concurrent searches.
+type GreatDeluge struct {
+	Target float64 // energy sought at the end of each run
+	Iter   int     // number of iterations in which the level falls to Target, typically Schedule.Iter
+	Rain   float64 // fall of the level per iteration; zero means (initial level - Target) / Iter
+	Margin float64 // height of the initial level above the starting energy of each run
+
+	Level float64 // current water level
+
+	run     int
+	started bool
+	rain    float64 // fall of the level per iteration in the current run
+	iter    int     // iteration at which Level was last lowered
+}
+
+// ErrDelugeIter is returned by GreatDeluge.Accept if it has neither Rain nor a positive Iter.
+var ErrDelugeIter = errors.New("anneal: GreatDeluge needs Rain or a positive Iter")
+
+// Accept adopts d.Proposed if its energy does not exceed the current energy or the water level,
+// and lowers the level.
+func (gd *GreatDeluge) Accept(d Decision) (bool, error) {
+	if !gd.started || d.Run != gd.run {
+		if gd.Rain == 0 && gd.Iter <= 0 {
+			return false, ErrDelugeIter
+		}
+		gd.Level, gd.run, gd.started, gd.iter = d.Energy+gd.Margin, d.Run, true, d.Iter
+		gd.rain = gd.Rain
+		if gd.rain == 0 {
+			gd.rain = (gd.Level - gd.Target) / float64(gd.Iter)
+		}
+	}
+	gd.Level -= gd.rain * float64(d.Iter-gd.iter)
+	gd.iter = d.Iter
+	return d.Delta <= 0 || d.Proposal <= gd.Level, nil
+}
@@ -0,0 +1,54 @@
+package anneal
+
+import "testing"
+
+func TestGreatDelugePenalty(t *testing.T) {
+	for _, test := range []struct {
+		name            string
+		proposal        float64 // energy of the proposal
+		penalty, penCur float64 // diversification penalties of the proposal and the current State
+		want            bool
+	}{
+		{"below the level", 14, 0, 0, true},
+		{"above the level", 16, 0, 0, false},
+		{"penalized below the level", 14, 3, 0, true},
+		{"above the level from a penalized State", 17, 0, 6, false},
+		{"improving when penalized", 20, 0, 11, true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			// The level begins at the current energy 10 plus the Margin 5.
+			gd := &GreatDeluge{Rain: 1e-9, Margin: 5}
+			const e = 10
+			ok, err := gd.Accept(Decision{
+				Delta:    (test.proposal + test.penalty) - (e + test.penCur),
+				Energy:   e,
+				Proposal: test.proposal,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ok != test.want {
+				t.Errorf("Accept = %t at level %v, want %t", ok, gd.Level, test.want)
+			}
+		})
+	}
+}
+
+func TestDecisionProposal(t *testing.T) {
+	// With restarts that diversify, Delta includes penalties, but Proposal is the energy of the proposal alone.
+	var penalized bool
+	acc := AcceptorFunc(func(d Decision) (bool, error) {
+		e := d.Proposed.Energy()
+		if d.Proposal != e {
+			t.Fatalf("Proposal = %v, want the energy %v of Proposed", d.Proposal, e)
+		}
+		penalized = penalized || d.Delta != e-d.Energy
+		return d.Delta <= 0, nil
+	})
+	if _, err := Run(&seededLine{x: 50}, WithIterations(1000), WithRestarts(2, 1), WithSeed(1), WithAcceptor(acc)); err != nil {
+		t.Fatal(err)
+	}
+	if !penalized {
+		t.Error("no Delta included a penalty")
+	}
+}
@@ -0,0 +1,229 @@
+/*
+Package trace records the progress of searches by package anneal and renders it for inspection,
+so that the effect of a Schedule can be judged at a glance while tuning it.
+
+A Recorder is an anneal.Observer that keeps every progress report. WriteSVG plots the reports as an SVG image
+with no dependencies beyond the standard library: the energies of the current and best States above,
+and the temperature below, on a logarithmic scale when it is positive throughout.
+WriteData writes the reports as whitespace-separated columns for gnuplot or a similar tool:
+
+	r := new(trace.Recorder)
+	best, err := anneal.Anneal(s, anneal.WithObserver(r, 1000))
+	...
+	err = trace.WriteSVG(f, r.Reports())
+
+The iterations of successive runs are laid end to end, so that a search with restarts appears as one continuous history.
+*/
+package trace
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"sync"
+
+	"github.com/dkmccandless/anneal"
+)
+
+// A Recorder is an anneal.Observer that records every progress report. It is safe for concurrent use.
+type Recorder struct {
+	mu      sync.Mutex
+	reports []anneal.Progress
+}
+
+// Observe records p.
+func (r *Recorder) Observe(p anneal.Progress) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p.Labels = nil
+	r.reports = append(r.reports, p)
+}
+
+// Reports returns the reports recorded so far, in order.
+func (r *Recorder) Reports() []anneal.Progress {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]anneal.Progress(nil), r.reports...)
+}
+
+// steps returns the position of each report along the whole search, with the iterations of each run following those of the previous one.
+func steps(reports []anneal.Progress) []int {
+	x := make([]int, len(reports))
+	var offset, last int
+	for i, p := range reports {
+		if i > 0 && p.Run != reports[i-1].Run {
+			offset += last
+		}
+		last = p.Iter
+		x[i] = offset + p.Iter
+	}
+	return x
+}
+
+// WriteData writes reports to w as a table for gnuplot: a comment line naming the columns step, run, iter,
+// temperature, energy, best, accepted, and elapsed seconds, then one line per report, with a blank line between runs
+// so that gnuplot does not join their curves. For example,
+//
+//	plot "trace.dat" using 1:5 with lines title "energy", "" using 1:6 with lines title "best"
+func WriteData(w io.Writer, reports []anneal.Progress) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "# step run iter temperature energy best accepted elapsed")
+	x := steps(reports)
+	for i, p := range reports {
+		if i > 0 && p.Run != reports[i-1].Run {
+			fmt.Fprintln(bw)
+		}
+		fmt.Fprintf(bw, "%d %d %d %g %g %g %d %g\n",
+			x[i], p.Run, p.Iter, p.Temperature, p.Energy, p.Best, p.Accepted, p.Elapsed.Seconds())
+	}
+	return bw.Flush()
+}
+
+// Dimensions of the image drawn by WriteSVG, and of its panels.
+const (
+	width, height = 720, 480
+	left, right   = 80, 20 // horizontal margins of the panels
+	top, gap      = 30, 40 // space above the upper panel and between the panels
+	bottom        = 40     // space below the lower panel
+	energyH       = 260    // height of the energy panel
+	tempH         = height - top - energyH - gap - bottom
+	plotW         = width - left - right
+)
+
+// A scale maps values in [lo, hi] onto the vertical extent of a panel.
+type scale struct {
+	lo, hi float64
+	log    bool
+	y, h   float64 // top and height of the panel
+}
+
+// newScale returns the scale of a panel spanning the finite values of vals, taking logarithms if log is set.
+func newScale(log bool, y, h float64, vals ...[]float64) scale {
+	s := scale{lo: math.Inf(1), hi: math.Inf(-1), log: log, y: y, h: h}
+	for _, v := range vals {
+		for _, v := range v {
+			if v, ok := s.value(v); ok {
+				s.lo, s.hi = min(s.lo, v), max(s.hi, v)
+			}
+		}
+	}
+	if s.lo > s.hi {
+		s.lo, s.hi = 0, 1
+	}
+	if s.lo == s.hi {
+		s.lo, s.hi = s.lo-1, s.hi+1
+	}
+	return s
+}
+
+// value returns v as plotted, and whether it can be.
+func (s scale) value(v float64) (float64, bool) {
+	if s.log {
+		v = math.Log10(v)
+	}
+	return v, !math.IsInf(v, 0) && !math.IsNaN(v)
+}
+
+// pos returns the vertical position of v, and whether it can be plotted.
+func (s scale) pos(v float64) (float64, bool) {
+	v, ok := s.value(v)
+	return s.y + s.h - (v-s.lo)*s.h/(s.hi-s.lo), ok
+}
+
+// label returns the text of the value at the top (hi) or bottom of the scale.
+func (s scale) label(hi bool) string {
+	v := s.lo
+	if hi {
+		v = s.hi
+	}
+	if s.log {
+		v = math.Pow(10, v)
+	}
+	return fmt.Sprintf("%.4g", v)
+}
+
+// WriteSVG writes to w an SVG image plotting reports: the energies of the current State in blue
+// and of the best State in red in the upper panel, and the temperature below,
+// with dashed lines marking the start of each run after the first.
+// Non-finite values are omitted. WriteSVG reports an error if there are fewer than two reports.
+func WriteSVG(w io.Writer, reports []anneal.Progress) error {
+	if len(reports) < 2 {
+		return errors.New("trace: fewer than two reports to plot")
+	}
+	x := steps(reports)
+	xmax := max(x[len(x)-1], 1)
+	xpos := func(i int) float64 { return left + float64(x[i])*plotW/float64(xmax) }
+
+	energy, best, temp := make([]float64, len(reports)), make([]float64, len(reports)), make([]float64, len(reports))
+	positive := true
+	for i, p := range reports {
+		energy[i], best[i], temp[i] = p.Energy, p.Best, p.Temperature
+		positive = positive && p.Temperature > 0
+	}
+	es := newScale(false, top, energyH, energy, best)
+	ts := newScale(positive, top+energyH+gap, tempH, temp)
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="12">`+"\n",
+		width, height, width, height)
+	fmt.Fprintf(bw, `<rect width="%d" height="%d" fill="white"/>`+"\n", width, height)
+	for _, s := range []scale{es, ts} {
+		fmt.Fprintf(bw, `<rect x="%d" y="%g" width="%d" height="%g" fill="none" stroke="gray"/>`+"\n", left, s.y, plotW, s.h)
+		fmt.Fprintf(bw, `<text x="%d" y="%g" text-anchor="end">%s</text>`+"\n", left-6, s.y+10, s.label(true))
+		fmt.Fprintf(bw, `<text x="%d" y="%g" text-anchor="end">%s</text>`+"\n", left-6, s.y+s.h, s.label(false))
+	}
+	for i := 1; i < len(reports); i++ {
+		if reports[i].Run != reports[i-1].Run {
+			for _, s := range []scale{es, ts} {
+				fmt.Fprintf(bw, `<line x1="%.1f" y1="%g" x2="%.1f" y2="%g" stroke="lightgray" stroke-dasharray="4 4"/>`+"\n",
+					xpos(i), s.y, xpos(i), s.y+s.h)
+			}
+		}
+	}
+	polyline(bw, reports, xpos, es, energy, "steelblue")
+	polyline(bw, reports, xpos, es, best, "firebrick")
+	polyline(bw, reports, xpos, ts, temp, "darkorange")
+
+	fmt.Fprintf(bw, `<text x="%d" y="%d">energy (current in blue, best in red)</text>`+"\n", left, top-10)
+	tempTitle := "temperature"
+	if ts.log {
+		tempTitle += " (logarithmic)"
+	}
+	fmt.Fprintf(bw, `<text x="%d" y="%d">%s</text>`+"\n", left, int(ts.y)-10, tempTitle)
+	fmt.Fprintf(bw, `<text x="%d" y="%d">0</text>`+"\n", left, height-bottom+16)
+	fmt.Fprintf(bw, `<text x="%d" y="%d" text-anchor="end">%d</text>`+"\n", width-right, height-bottom+16, xmax)
+	fmt.Fprintf(bw, `<text x="%d" y="%d" text-anchor="middle">iteration</text>`+"\n", left+plotW/2, height-bottom+16)
+	fmt.Fprintln(bw, "</svg>")
+	return bw.Flush()
+}
+
+// polyline draws the values vals of reports as lines of the given color, broken at non-finite values and between runs.
+func polyline(w io.Writer, reports []anneal.Progress, xpos func(int) float64, s scale, vals []float64, color string) {
+	var (
+		b strings.Builder
+		n int // number of points in b
+	)
+	flush := func() {
+		if n > 1 {
+			fmt.Fprintf(w, `<polyline fill="none" stroke="%s" points="%s"/>`+"\n", color, strings.TrimSpace(b.String()))
+		}
+		b.Reset()
+		n = 0
+	}
+	for i, v := range vals {
+		if i > 0 && reports[i].Run != reports[i-1].Run {
+			flush()
+		}
+		y, ok := s.pos(v)
+		if !ok {
+			flush()
+			continue
+		}
+		fmt.Fprintf(&b, "%.1f,%.1f ", xpos(i), y)
+		n++
+	}
+	flush()
+}
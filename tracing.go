@@ -0,0 +1,92 @@
+package anneal
+
+import (
+	"maps"
+	"slices"
+)
+
+// A Tracer records searches as spans for distributed tracing, so that a search embedded in a request pipeline
+// appears in its traces. The interface is small enough to adapt to OpenTelemetry or another tracing system
+// without package anneal depending on it; an adapter typically captures the parent context:
+//
+//	type otelTracer struct {
+//		ctx    context.Context
+//		tracer trace.Tracer
+//	}
+//
+//	func (t otelTracer) Start(name string, attrs ...anneal.Attribute) anneal.Span {
+//		_, span := t.tracer.Start(t.ctx, name, trace.WithAttributes(otelAttrs(attrs)...))
+//		return otelSpan{span}
+//	}
+//
+// where otelSpan implements AddEvent and End by calling the methods of the same names,
+// together with RecordError and SetStatus for a non-nil error,
+// and otelAttrs converts each Attribute's value with attribute.String, attribute.Int, and so on.
+type Tracer interface {
+	// Start begins a span for a search, described by attrs.
+	Start(name string, attrs ...Attribute) Span
+}
+
+// A Span is the record of one search.
+//
+// A search begins its span named "anneal" with attributes for its labels, prefixed by "label.",
+// and for the Schedule fields iter, ti, tf, and restarts.
+// It adds the event "restart" at the start of each restart, which reheats the search to Schedule.Ti,
+// with attributes run, energy, and, when an Initializer constructed the starting State, initializer;
+// the event "best" at the end of each run that improved on the best State, with attributes run, energy, and temperature;
+// and the event "polish" at the start of the finishing descent, with attribute energy.
+// It ends the span with attributes energy, evaluations, and runs when the search is complete,
+// stops with an error, or is closed before completion, in which case the attribute abandoned is true.
+type Span interface {
+	// AddEvent records an event in the span.
+	AddEvent(name string, attrs ...Attribute)
+
+	// End ends the span, recording the error that ended the search, if any.
+	End(err error, attrs ...Attribute)
+}
+
+// An Attribute describes a span or an event. Its Value is a string, int, float64, or bool.
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+// startSpan begins the span of the search by an, if a Tracer is in use.
+func (an *Annealer) startSpan() {
+	if an.sch.Tracer == nil {
+		return
+	}
+	var attrs []Attribute
+	for _, k := range slices.Sorted(maps.Keys(an.a.labels)) {
+		attrs = append(attrs, Attribute{"label." + k, an.a.labels[k]})
+	}
+	attrs = append(attrs,
+		Attribute{"iter", an.sch.Iter},
+		Attribute{"ti", an.sch.Ti},
+		Attribute{"tf", an.sch.Tf},
+		Attribute{"restarts", an.sch.Restarts},
+	)
+	an.span = an.sch.Tracer.Start("anneal", attrs...)
+}
+
+// event adds an event to the span, if any.
+func (an *Annealer) event(name string, attrs ...Attribute) {
+	if an.span != nil {
+		an.span.AddEvent(name, attrs...)
+	}
+}
+
+// endSpan ends the span, if any, with the final statistics of the search.
+func (an *Annealer) endSpan(attrs ...Attribute) {
+	if an.span == nil {
+		return
+	}
+	a := an.a
+	attrs = append([]Attribute{
+		{"energy", a.ebest},
+		{"evaluations", a.evals},
+		{"runs", a.runs},
+	}, attrs...)
+	an.span.End(an.err, attrs...)
+	an.span = nil
+}
@@ -0,0 +1,217 @@
+package anneal
+
+import "math/rand"
+
+// A TreeMove is a set of kinds of moves on a tree.
+type TreeMove int
+
+const (
+	TreeSwap   TreeMove = 1 << iota // exchange two disjoint subtrees
+	TreePrune                       // replace a subtree by a new leaf
+	TreeGraft                       // replace a subtree by a new subtree
+	TreeMutate                      // replace the value of a node, keeping its children
+
+	TreeAll = TreeSwap | TreePrune | TreeGraft | TreeMutate
+)
+
+// A TreeNode is a node of a tree holding a value of type T, such as an operator or a terminal of an expression.
+type TreeNode[T any] struct {
+	Value    T
+	Children []*TreeNode[T]
+}
+
+// Size returns the number of nodes in the tree rooted at n.
+func (n *TreeNode[T]) Size() int {
+	size := 1
+	for _, c := range n.Children {
+		size += c.Size()
+	}
+	return size
+}
+
+// Depth returns the number of nodes on the longest path from n to a leaf, counting both.
+func (n *TreeNode[T]) Depth() int {
+	var d int
+	for _, c := range n.Children {
+		d = max(d, c.Depth())
+	}
+	return d + 1
+}
+
+// Clone returns a deep copy of the tree rooted at n.
+func (n *TreeNode[T]) Clone() *TreeNode[T] {
+	c := &TreeNode[T]{Value: n.Value}
+	if n.Children != nil {
+		c.Children = make([]*TreeNode[T], len(n.Children))
+		for i, child := range n.Children {
+			c.Children[i] = child.Clone()
+		}
+	}
+	return c
+}
+
+// A TreeProblem describes the minimization of a function of a tree, as in program synthesis,
+// decision tree learning, and the design of hierarchies.
+// Moves that need a function the problem lacks, such as TreeMutate without Mutate, are not used.
+type TreeProblem[T any] struct {
+	Func func(root *TreeNode[T]) float64 // objective function; must not retain or modify the tree
+
+	// Mutate returns a random replacement for the value v of a node with arity children,
+	// such as another operator of the same arity.
+	Mutate func(v T, arity int) T
+
+	// Grow returns a random tree of depth at most depth, which is at least 1. Grow(1) must return a leaf.
+	Grow func(depth int) *TreeNode[T]
+
+	MaxDepth int // largest depth of a tree, or 0 for no limit; grafts are grown no deeper than it allows, or than 4 otherwise
+	MaxSize  int // largest number of nodes in a tree, or 0 for no limit
+
+	Moves TreeMove // kinds of moves to choose among uniformly; zero means TreeAll
+}
+
+// NewState returns a TreeState of p holding the tree rooted at root. It does not copy the tree.
+func (p *TreeProblem[T]) NewState(root *TreeNode[T]) *TreeState[T] {
+	return &TreeState[T]{Root: root, p: p}
+}
+
+// A TreeState is a tree in the search space of a TreeProblem. Its energy is the value of the objective function.
+// Each neighbor is a copy of the tree altered by a single move chosen uniformly from the kinds the problem allows.
+// A move whose result would exceed MaxDepth or MaxSize is redrawn, and after several failures
+// the neighbor is an unaltered copy.
+// TreeState implements Seeded; the functions of the problem draw from sources of their own.
+type TreeState[T any] struct {
+	Root *TreeNode[T]
+	p    *TreeProblem[T]
+	rand source
+}
+
+// Energy returns the value of the objective function at s.Root.
+func (s *TreeState[T]) Energy() float64 { return s.p.Func(s.Root) }
+
+// treeRetries is the number of moves that Neighbor draws before giving up on satisfying the constraints.
+const treeRetries = 8
+
+// Neighbor returns a State that differs from s by a randomly chosen move.
+func (s *TreeState[T]) Neighbor() State {
+	kinds := s.p.kinds()
+	for range treeRetries {
+		if len(kinds) == 0 {
+			break
+		}
+		root := s.Root.Clone()
+		nodes := treeNodes(root)
+		var ok bool
+		switch kinds[s.rand.Intn(len(kinds))] {
+		case TreeSwap:
+			ok = swapSubtrees(nodes, s.rand)
+		case TreePrune:
+			at := nodes[s.rand.Intn(len(nodes))]
+			root, ok = at.replace(root, s.p.Grow(1)), true
+		case TreeGraft:
+			at := nodes[s.rand.Intn(len(nodes))]
+			depth := 4
+			if s.p.MaxDepth > 0 {
+				depth = max(s.p.MaxDepth-at.depth+1, 1)
+			}
+			root, ok = at.replace(root, s.p.Grow(depth)), true
+		case TreeMutate:
+			n := nodes[s.rand.Intn(len(nodes))].n
+			n.Value, ok = s.p.Mutate(n.Value, len(n.Children)), true
+		}
+		if ok && s.p.fits(root) {
+			return &TreeState[T]{Root: root, p: s.p, rand: s.rand}
+		}
+	}
+	return &TreeState[T]{Root: s.Root.Clone(), p: s.p, rand: s.rand}
+}
+
+// UseRand sets the source of randomness of s and its neighbors.
+func (s *TreeState[T]) UseRand(r *rand.Rand) { s.rand.r = r }
+
+// fits reports whether the tree rooted at root satisfies the constraints of p.
+func (p *TreeProblem[T]) fits(root *TreeNode[T]) bool {
+	return (p.MaxDepth <= 0 || root.Depth() <= p.MaxDepth) && (p.MaxSize <= 0 || root.Size() <= p.MaxSize)
+}
+
+// kinds returns the individual kinds of moves that p allows and can perform.
+func (p *TreeProblem[T]) kinds() []TreeMove {
+	moves := p.Moves & TreeAll
+	if moves == 0 {
+		moves = TreeAll
+	}
+	if p.Grow == nil {
+		moves &^= TreePrune | TreeGraft
+	}
+	if p.Mutate == nil {
+		moves &^= TreeMutate
+	}
+	return treeKinds[moves]
+}
+
+// treeKinds lists the individual kinds of moves in each set.
+var treeKinds = func() (t [TreeAll + 1][]TreeMove) {
+	for moves := range t {
+		for _, m := range []TreeMove{TreeSwap, TreePrune, TreeGraft, TreeMutate} {
+			if TreeMove(moves)&m != 0 {
+				t[moves] = append(t[moves], m)
+			}
+		}
+	}
+	return t
+}()
+
+// A treeRef locates a node within a tree.
+type treeRef[T any] struct {
+	n      *TreeNode[T]
+	parent *TreeNode[T] // or nil for the root
+	index  int          // index of n among the children of parent
+	depth  int          // depth of n, counting the root as 1
+	size   int          // number of nodes in the subtree rooted at n
+}
+
+// replace replaces the subtree at r by n and returns the root of the resulting tree.
+func (r treeRef[T]) replace(root, n *TreeNode[T]) *TreeNode[T] {
+	if r.parent == nil {
+		return n
+	}
+	r.parent.Children[r.index] = n
+	return root
+}
+
+// treeNodes returns references to the nodes of the tree rooted at root in preorder,
+// so that the descendants of the node at position i are those at positions i+1 through i+size-1.
+func treeNodes[T any](root *TreeNode[T]) []treeRef[T] {
+	var refs []treeRef[T]
+	var walk func(n, parent *TreeNode[T], index, depth int) int
+	walk = func(n, parent *TreeNode[T], index, depth int) int {
+		k := len(refs)
+		refs = append(refs, treeRef[T]{n: n, parent: parent, index: index, depth: depth})
+		size := 1
+		for i, c := range n.Children {
+			size += walk(c, n, i, depth+1)
+		}
+		refs[k].size = size
+		return size
+	}
+	walk(root, nil, 0, 1)
+	return refs
+}
+
+// swapSubtrees exchanges two randomly chosen disjoint subtrees of the tree whose nodes are listed in preorder,
+// drawing from src, and reports whether it found two.
+func swapSubtrees[T any](nodes []treeRef[T], src source) bool {
+	for range treeRetries {
+		i, j := src.Intn(len(nodes)), src.Intn(len(nodes))
+		if i > j {
+			i, j = j, i
+		}
+		a, b := nodes[i], nodes[j]
+		if j < i+a.size {
+			// The subtree at i contains the node at j.
+			continue
+		}
+		a.parent.Children[a.index], b.parent.Children[b.index] = b.n, a.n
+		return true
+	}
+	return false
+}
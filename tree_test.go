@@ -0,0 +1,126 @@
+package anneal
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// treeArity gives the number of children of each value of the expression trees of the tests.
+var treeArity = map[string]int{"x": 0, "1": 0, "neg": 1, "exp": 1, "+": 2, "*": 2}
+
+// growTree returns a random expression tree of depth at most depth.
+func growTree(depth int) *TreeNode[string] {
+	if depth <= 1 || rand.Intn(3) == 0 {
+		return &TreeNode[string]{Value: []string{"x", "1"}[rand.Intn(2)]}
+	}
+	n := &TreeNode[string]{Value: []string{"neg", "exp", "+", "*"}[rand.Intn(4)]}
+	for range treeArity[n.Value] {
+		n.Children = append(n.Children, growTree(depth-1))
+	}
+	return n
+}
+
+// mutateTree returns another value of the same arity as v.
+func mutateTree(v string, arity int) string {
+	var values []string
+	for w, a := range treeArity {
+		if a == arity && w != v {
+			values = append(values, w)
+		}
+	}
+	return values[rand.Intn(len(values))]
+}
+
+// treeString returns a representation of the tree rooted at n.
+func treeString(n *TreeNode[string]) string {
+	if len(n.Children) == 0 {
+		return n.Value
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "(%s", n.Value)
+	for _, c := range n.Children {
+		fmt.Fprintf(&b, " %s", treeString(c))
+	}
+	b.WriteString(")")
+	return b.String()
+}
+
+// checkTree reports an error unless the tree rooted at root is a tree, rather than a graph with shared nodes,
+// whose nodes each have as many children as their values require, and which shares no node with seen.
+func checkTree(root *TreeNode[string], seen map[*TreeNode[string]]bool) error {
+	if seen[root] {
+		return fmt.Errorf("node %s appears twice", treeString(root))
+	}
+	seen[root] = true
+	if a, ok := treeArity[root.Value]; !ok || a != len(root.Children) {
+		return fmt.Errorf("node %q has %d children", root.Value, len(root.Children))
+	}
+	for _, c := range root.Children {
+		if c == nil {
+			return fmt.Errorf("node %q has a nil child", root.Value)
+		}
+		if err := checkTree(c, seen); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestTreeNeighbor(t *testing.T) {
+	for _, test := range []struct {
+		name  string
+		moves TreeMove
+	}{
+		{"swap", TreeSwap},
+		{"prune", TreePrune},
+		{"graft", TreeGraft},
+		{"mutate", TreeMutate},
+		{"all", TreeAll},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			const maxDepth, maxSize = 6, 25
+			p := &TreeProblem[string]{
+				Func:     func(root *TreeNode[string]) float64 { return float64(root.Size()) },
+				Mutate:   mutateTree,
+				Grow:     growTree,
+				MaxDepth: maxDepth,
+				MaxSize:  maxSize,
+				Moves:    test.moves,
+			}
+			leaf := func(v string) *TreeNode[string] { return &TreeNode[string]{Value: v} }
+			node := func(v string, children ...*TreeNode[string]) *TreeNode[string] {
+				return &TreeNode[string]{Value: v, Children: children}
+			}
+			s := p.NewState(node("+", node("*", leaf("x"), leaf("1")), node("neg", node("exp", leaf("x")))))
+			var changed int
+			const steps = 2000
+			for range steps {
+				before := treeString(s.Root)
+				n := s.Neighbor().(*TreeState[string])
+				if got := treeString(s.Root); got != before {
+					t.Fatalf("Neighbor changed the tree from %s to %s", before, got)
+				}
+				parent := make(map[*TreeNode[string]]bool)
+				if err := checkTree(s.Root, parent); err != nil {
+					t.Fatal(err)
+				}
+				if err := checkTree(n.Root, parent); err != nil {
+					t.Fatalf("neighbor %s of %s: %v", treeString(n.Root), before, err)
+				}
+				if d, size := n.Root.Depth(), n.Root.Size(); d > maxDepth || size > maxSize {
+					t.Fatalf("neighbor %s has depth %d and size %d, want at most %d and %d", treeString(n.Root), d, size, maxDepth, maxSize)
+				}
+				if treeString(n.Root) != before {
+					changed++
+				}
+				s = n
+			}
+			// Swaps of equal subtrees and grafts of equal trees leave the tree unchanged, but most moves do not.
+			if changed < steps/4 {
+				t.Errorf("%d of %d neighbors differ from their States", changed, steps)
+			}
+		})
+	}
+}
@@ -0,0 +1,6 @@
+//go:build race
+
+package tsp
+
+// raceEnabled reports whether the race detector is enabled, which makes sync.Pool drop released values at random.
+const raceEnabled = true
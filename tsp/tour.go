@@ -0,0 +1,207 @@
+package tsp
+
+import (
+	"iter"
+	"math/rand"
+	"slices"
+	"sync"
+
+	"github.com/dkmccandless/anneal"
+)
+
+// NewTour returns a Tour of in visiting the cities in order, which must be a permutation of [0, in.Len()).
+// It does not copy order.
+func (in *Instance) NewTour(order []int) *Tour {
+	return &Tour{Order: order, in: in, length: in.Length(order)}
+}
+
+// RandomTour returns a Tour of in visiting the cities in a uniformly random order.
+func (in *Instance) RandomTour() *Tour {
+	return in.NewTour(rand.Perm(in.n))
+}
+
+// Candidates returns the k nearest neighbors of each city of in.
+// It computes them from the coordinates of the cities if their distances are planar, and from Dist otherwise.
+func (in *Instance) Candidates(k int) *anneal.Candidates {
+	switch in.weight {
+	case "EUC_2D", "CEIL_2D", "ATT":
+		return anneal.NewCandidatesXY(in.x, in.y, k)
+	}
+	return anneal.NewCandidates(in.n, k, func(i, j int) float64 { return float64(in.Dist(i, j)) })
+}
+
+// A Tour is a closed tour of the cities of an Instance.
+// Its energy is its length, and each neighbor differs from it by a 2-opt move,
+// chosen uniformly or, after UseCandidates, among the moves that join a city to one of its candidates.
+// It implements anneal.IntEnergy, anneal.Recomputer, anneal.Enumerator, anneal.Seeded, anneal.AcceptNotifier,
+// and anneal.Recycler by drawing the storage of its neighbors from a pool.
+type Tour struct {
+	Order  []int
+	in     *Instance
+	length int64
+	rand   *rand.Rand // source set by UseRand, or nil for the global source
+	near   *nearby    // candidate lists set by UseCandidates, or nil
+	pos    []int      // position of each city in Order, if near is not nil
+	ends   [4]int     // cities whose edges the move that produced the Tour changed
+}
+
+// A nearby holds the candidate lists and don't-look bits shared by a Tour and the Tours derived from it.
+type nearby struct {
+	cand *anneal.Candidates
+	mu   sync.Mutex // guards look, since Workers may propose neighbors of one Tour concurrently
+	look *anneal.DontLook
+}
+
+// Energy returns the length of t.
+func (t *Tour) Energy() float64 { return float64(t.length) }
+
+// EnergyInt returns the length of t.
+func (t *Tour) EnergyInt() int64 { return t.length }
+
+// Length returns the length of t.
+func (t *Tour) Length() int64 { return t.length }
+
+// RecomputeEnergy returns the length of t computed from scratch.
+func (t *Tour) RecomputeEnergy() float64 { return float64(t.in.Length(t.Order)) }
+
+// Neighbor returns a Tour that differs from t by a randomly chosen 2-opt move.
+func (t *Tour) Neighbor() anneal.State {
+	n := len(t.Order)
+	dst := tours.Get().(*Tour)
+	if n < 4 {
+		return t.CloneInto(dst)
+	}
+	if t.near != nil {
+		if i, j, ok := t.candidate(); ok {
+			return t.twoOpt(dst, i, j)
+		}
+	}
+	// Choose distinct, nonadjacent edges (i, i+1) and (j, j+1) with i < j.
+	i, j := t.intn(n), t.intn(n-3)
+	j = (i + 2 + j) % n
+	if j < i {
+		i, j = j, i
+	}
+	return t.twoOpt(dst, i, j)
+}
+
+// UseCandidates restricts the neighbors of t and the Tours derived from it to the 2-opt moves
+// that join a city to one of its candidates in c, which must list the cities of the Instance of t.
+// The cities are taken in turn, using don't-look bits to skip those whose edges no adopted move has changed
+// since their last turn, until every city is asleep and all are woken again.
+// If the move for a city is impossible, because its candidate is next to it in the tour, the neighbor is chosen uniformly.
+// The don't-look bits are shared by every Tour derived from t, including those of concurrent searches.
+func (t *Tour) UseCandidates(c *anneal.Candidates) {
+	t.near = &nearby{cand: c, look: anneal.NewDontLook(len(t.Order))}
+	t.pos = slices.Grow(t.pos[:0], len(t.Order))[:len(t.Order)]
+	for k, city := range t.Order {
+		t.pos[city] = k
+	}
+}
+
+// candidate returns the positions i < j of the 2-opt move that joins the next active city to a random one of its candidates,
+// or false if that move is impossible.
+func (t *Tour) candidate() (i, j int, ok bool) {
+	nb := t.near
+	nb.mu.Lock()
+	a, ok := nb.look.Next()
+	if !ok {
+		for city := range t.Order {
+			nb.look.Wake(city)
+		}
+		a, _ = nb.look.Next()
+	}
+	nb.mu.Unlock()
+	near := nb.cand.Near(a)
+	if len(near) == 0 {
+		return 0, 0, false
+	}
+	i, j = t.pos[a], t.pos[near[t.intn(len(near))]]
+	if i > j {
+		i, j = j, i
+	}
+	// The move replaces the edges leaving positions i and j, which must be distinct and nonadjacent.
+	return i, j, j-i >= 2 && !(i == 0 && j == len(t.Order)-1)
+}
+
+// OnAccept wakes the cities whose edges the move that produced t changed, if t uses candidate lists.
+func (t *Tour) OnAccept(prev, next anneal.State) {
+	if t.near == nil {
+		return
+	}
+	t.near.mu.Lock()
+	for _, city := range t.ends {
+		t.near.look.Wake(city)
+	}
+	t.near.mu.Unlock()
+}
+
+// UseRand sets the source of randomness of t and its neighbors.
+func (t *Tour) UseRand(r *rand.Rand) { t.rand = r }
+
+// intn returns a random number in [0, n) from the source of t.
+func (t *Tour) intn(n int) int {
+	if t.rand == nil {
+		return rand.Intn(n)
+	}
+	return t.rand.Intn(n)
+}
+
+// Release returns the storage of t to the pool from which Neighbor draws.
+func (t *Tour) Release() { tours.Put(t) }
+
+// tours holds released Tours for reuse.
+var tours = sync.Pool{New: func() any { return new(Tour) }}
+
+// TwoOpt returns the Tour that replaces the edges leaving positions i and j, where i < j,
+// by reversing the segment Order[i+1 : j+1].
+func (t *Tour) TwoOpt(i, j int) *Tour { return t.twoOpt(nil, i, j) }
+
+// twoOpt copies t into dst, applies the 2-opt move on positions i and j to it, and returns it.
+func (t *Tour) twoOpt(dst *Tour, i, j int) *Tour {
+	n := len(t.Order)
+	a, b := t.Order[i], t.Order[i+1]
+	c, d := t.Order[j], t.Order[(j+1)%n]
+	delta := t.in.Dist(a, c) + t.in.Dist(b, d) - t.in.Dist(a, b) - t.in.Dist(c, d)
+	dst = t.CloneInto(dst)
+	slices.Reverse(dst.Order[i+1 : j+1])
+	if dst.near != nil {
+		for k := i + 1; k <= j; k++ {
+			dst.pos[dst.Order[k]] = k
+		}
+	}
+	dst.length += delta
+	dst.ends = [4]int{a, b, c, d}
+	return dst
+}
+
+// Neighborhood returns an iterator over every Tour that differs from t by a single 2-opt move.
+func (t *Tour) Neighborhood() iter.Seq[anneal.State] {
+	return func(yield func(anneal.State) bool) {
+		n := len(t.Order)
+		for i := 0; i < n; i++ {
+			for j := i + 2; j < n; j++ {
+				if i == 0 && j == n-1 {
+					// The edges are adjacent.
+					continue
+				}
+				if !yield(t.TwoOpt(i, j)) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// CloneInto copies t into dst, reusing the storage of dst.Order if it is large enough, and returns dst.
+// If dst is nil, it allocates a new Tour. Neighbor uses it to fill released Tours;
+// programs that manage their own pools of States can use it likewise.
+func (t *Tour) CloneInto(dst *Tour) *Tour {
+	if dst == nil {
+		dst = new(Tour)
+	}
+	dst.Order = append(dst.Order[:0], t.Order...)
+	dst.pos = append(dst.pos[:0], t.pos...)
+	dst.in, dst.length, dst.rand, dst.near, dst.ends = t.in, t.length, t.rand, t.near, t.ends
+	return dst
+}
@@ -0,0 +1,149 @@
+package tsp
+
+import (
+	"math/rand"
+	"runtime"
+	"slices"
+	"testing"
+
+	"github.com/dkmccandless/anneal"
+)
+
+func benchTour() *Tour { return randomTour(1000) }
+
+// randomTour returns a random Tour of n cities placed uniformly at random.
+func randomTour(n int) *Tour {
+	in := &Instance{n: n, weight: "EUC_2D", x: make([]float64, n), y: make([]float64, n)}
+	for i := range n {
+		in.x[i], in.y[i] = 1000*rand.Float64(), 1000*rand.Float64()
+	}
+	return in.RandomTour()
+}
+
+func TestNeighbor(t *testing.T) {
+	tour := benchTour()
+	for range 100 {
+		n := tour.Neighbor().(*Tour)
+		if l := n.in.Length(n.Order); n.Length() != l {
+			t.Fatalf("Length = %d, want %d", n.Length(), l)
+		}
+		n.Release()
+	}
+}
+
+func BenchmarkNeighbor(b *testing.B) {
+	tour := benchTour()
+	b.ReportAllocs()
+	for b.Loop() {
+		tour.Neighbor().(anneal.Recycler).Release()
+	}
+}
+
+func BenchmarkCloneInto(b *testing.B) {
+	tour := benchTour()
+	dst := tour.CloneInto(nil)
+	b.ReportAllocs()
+	for b.Loop() {
+		tour.CloneInto(dst)
+	}
+}
+
+func TestSteadyStateAllocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("the pool of released Tours is unreliable under the race detector")
+	}
+	// At this temperature every longer tour is rejected, so once the search has settled at a 2-optimal tour,
+	// every proposal is released to the pool and reused.
+	// An audit keeps the search from sprinting, so that each step is one iteration.
+	an, err := anneal.New(randomTour(20), anneal.WithIterations(1e12), anneal.WithTemperatures(1e-9, 1e-10), anneal.WithAudit(1<<30))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer an.Close()
+	// The search has settled once its current Tour is 2-optimal.
+	for !twoOptimal(an.Current().(*Tour)) {
+		if !an.Step() {
+			t.Fatal("search ended")
+		}
+	}
+	// Allocating per step or per proposal would appear many times over; the runtime's own bookkeeping accounts for a few.
+	const steps, maxRuntimeMallocs = 100000, 10
+	// Finish any collection in progress, which would empty the pool of released Tours.
+	runtime.GC()
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	for range steps {
+		an.Step()
+	}
+	runtime.ReadMemStats(&after)
+	if n := after.Mallocs - before.Mallocs; n > maxRuntimeMallocs {
+		t.Errorf("%d allocations in %d steps, want at most %d", n, steps, maxRuntimeMallocs)
+	}
+}
+
+// twoOptimal reports whether no 2-opt move shortens t.
+func twoOptimal(t *Tour) bool {
+	for n := range t.Neighborhood() {
+		if n.(*Tour).Length() < t.Length() {
+			return false
+		}
+	}
+	return true
+}
+
+func TestSeeded(t *testing.T) {
+	tour := randomTour(30)
+	run := func() []int {
+		best, err := anneal.Anneal(tour, anneal.WithIterations(2000), anneal.WithTemperatures(0.1, 1e-3), anneal.WithSeed(1))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return best.(*Tour).Order
+	}
+	if a, b := run(), run(); !slices.Equal(a, b) {
+		t.Errorf("seeded runs returned %v and %v", a, b)
+	}
+}
+
+func TestCandidates(t *testing.T) {
+	cur := randomTour(200)
+	cand := cur.in.Candidates(8)
+	cur.UseCandidates(cand)
+	near := func(a, b int) bool { return slices.Contains(cand.Near(a), b) || slices.Contains(cand.Near(b), a) }
+	var joined int
+	const steps = 1000
+	for range steps {
+		n := cur.Neighbor().(*Tour)
+		if l := n.in.Length(n.Order); n.Length() != l {
+			t.Fatalf("Length = %d, want %d", n.Length(), l)
+		}
+		for k, city := range n.Order {
+			if n.pos[city] != k {
+				t.Fatalf("city %d at position %d, but pos = %d", city, k, n.pos[city])
+			}
+		}
+		// The move replaced edges (a, b) and (c, d) by (a, c) and (b, d).
+		if a, c := n.ends[0], n.ends[2]; near(a, c) {
+			joined++
+		}
+		n.OnAccept(cur, n)
+		cur = n
+	}
+	if joined < steps/2 {
+		t.Errorf("%d of %d moves joined a city to a candidate", joined, steps)
+	}
+
+	tour := randomTour(200)
+	uniform, err := anneal.Anneal(tour, anneal.WithIterations(20000), anneal.WithTemperatures(0.1, 1e-4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tour.UseCandidates(tour.in.Candidates(8))
+	candidate, err := anneal.Anneal(tour, anneal.WithIterations(20000), anneal.WithTemperatures(0.1, 1e-4), anneal.WithWorkers(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l, u := candidate.(*Tour).Length(), uniform.(*Tour).Length(); l >= u {
+		t.Errorf("length with candidates = %d, uniform = %d", l, u)
+	}
+}
@@ -0,0 +1,368 @@
+/*
+Package tsp reads traveling salesman instances in the TSPLIB format and adapts them to package anneal.
+
+Parse reads an instance, and its Tour State visits every city once, with neighbors that differ by a 2-opt move:
+reversing a segment of the tour, which replaces two of its edges. Each Tour keeps its length up to date
+incrementally, so a proposal costs constant time to evaluate beyond copying the tour.
+On large instances, Tour.UseCandidates restricts the moves to those that join nearby cities,
+from the candidate lists that Instance.Candidates computes.
+Distances are computed by the rules of the TSPLIB specification, including its rounding to integers,
+so tour lengths are directly comparable with published results for the same instances,
+such as the optimal tours that ParseTour reads.
+
+Parse supports symmetric instances (TYPE: TSP) with the edge weight types EUC_2D, CEIL_2D, ATT, and GEO,
+and EXPLICIT weights in the formats FULL_MATRIX, UPPER_ROW, LOWER_ROW, UPPER_DIAG_ROW, and LOWER_DIAG_ROW.
+*/
+package tsp
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// An Instance is a symmetric traveling salesman problem. It is immutable after construction.
+type Instance struct {
+	Name    string
+	Comment string
+
+	n      int
+	weight string    // EDGE_WEIGHT_TYPE
+	x, y   []float64 // coordinates of the cities, unless weight is EXPLICIT
+	matrix [][]int64 // distances, if weight is EXPLICIT
+}
+
+// Len returns the number of cities.
+func (in *Instance) Len() int { return in.n }
+
+// Dist returns the distance between cities i and j, numbered from 0.
+func (in *Instance) Dist(i, j int) int64 {
+	if in.matrix != nil {
+		return in.matrix[i][j]
+	}
+	dx, dy := in.x[i]-in.x[j], in.y[i]-in.y[j]
+	switch in.weight {
+	case "CEIL_2D":
+		return int64(math.Ceil(math.Sqrt(dx*dx + dy*dy)))
+	case "ATT":
+		r := math.Sqrt((dx*dx + dy*dy) / 10)
+		t := math.Floor(r + 0.5)
+		if t < r {
+			t++
+		}
+		return int64(t)
+	case "GEO":
+		return geoDist(in.x[i], in.y[i], in.x[j], in.y[j])
+	}
+	return int64(math.Floor(math.Sqrt(dx*dx+dy*dy) + 0.5))
+}
+
+// geoDist returns the TSPLIB geographical distance in kilometers between two points
+// given as latitude and longitude in degrees and minutes, DDD.MM.
+func geoDist(lat1, lon1, lat2, lon2 float64) int64 {
+	const rrr = 6378.388
+	rad := func(x float64) float64 {
+		deg := math.Trunc(x)
+		return math.Pi * (deg + 5*(x-deg)/3) / 180
+	}
+	la1, lo1, la2, lo2 := rad(lat1), rad(lon1), rad(lat2), rad(lon2)
+	q1 := math.Cos(lo1 - lo2)
+	q2 := math.Cos(la1 - la2)
+	q3 := math.Cos(la1 + la2)
+	return int64(rrr*math.Acos(0.5*((1+q1)*q2-(1-q1)*q3)) + 1)
+}
+
+// Length returns the length of the closed tour visiting the cities in order.
+func (in *Instance) Length(order []int) int64 {
+	var l int64
+	for k, c := range order {
+		l += in.Dist(c, order[(k+1)%len(order)])
+	}
+	return l
+}
+
+// Parse reads an instance in the TSPLIB format from r.
+func Parse(r io.Reader) (*Instance, error) {
+	p := &parser{sc: bufio.NewScanner(r)}
+	p.sc.Buffer(nil, 1<<20)
+	in := &Instance{n: -1}
+	var format string
+	for p.next() {
+		line := strings.TrimSpace(p.line)
+		if line == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		switch {
+		case key == "EOF":
+			return in, in.check()
+		case key == "NODE_COORD_SECTION":
+			if err := p.coords(in); err != nil {
+				return nil, err
+			}
+		case key == "EDGE_WEIGHT_SECTION":
+			if err := p.weights(in, format); err != nil {
+				return nil, err
+			}
+		case key == "DISPLAY_DATA_SECTION":
+			if err := p.skip(in.n); err != nil {
+				return nil, err
+			}
+		case !ok:
+			return nil, p.errorf("unknown section %q", key)
+		case key == "NAME":
+			in.Name = value
+		case key == "COMMENT":
+			in.Comment = strings.TrimSpace(in.Comment + " " + value)
+		case key == "TYPE":
+			if value != "TSP" {
+				return nil, p.errorf("unsupported TYPE %s", value)
+			}
+		case key == "DIMENSION":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, p.errorf("invalid DIMENSION %q", value)
+			}
+			in.n = n
+		case key == "EDGE_WEIGHT_TYPE":
+			switch value {
+			case "EUC_2D", "CEIL_2D", "ATT", "GEO", "EXPLICIT":
+				in.weight = value
+			default:
+				return nil, p.errorf("unsupported EDGE_WEIGHT_TYPE %s", value)
+			}
+		case key == "EDGE_WEIGHT_FORMAT":
+			format = value
+		case key == "NODE_COORD_TYPE", key == "DISPLAY_DATA_TYPE":
+			// Coordinates are read as given, and display data is not used.
+		default:
+			return nil, p.errorf("unknown key %q", key)
+		}
+	}
+	if err := p.sc.Err(); err != nil {
+		return nil, err
+	}
+	// The EOF line is optional in practice.
+	return in, in.check()
+}
+
+// check reports whether in has the data its specification requires.
+func (in *Instance) check() error {
+	switch {
+	case in.n < 0:
+		return errors.New("tsp: missing DIMENSION")
+	case in.weight == "":
+		return errors.New("tsp: missing EDGE_WEIGHT_TYPE")
+	case in.weight == "EXPLICIT" && in.matrix == nil:
+		return errors.New("tsp: missing EDGE_WEIGHT_SECTION")
+	case in.weight != "EXPLICIT" && in.x == nil:
+		return errors.New("tsp: missing NODE_COORD_SECTION")
+	}
+	return nil
+}
+
+// A parser reads the lines of a TSPLIB file.
+type parser struct {
+	sc   *bufio.Scanner
+	line string
+	num  int // line number
+}
+
+func (p *parser) next() bool {
+	if !p.sc.Scan() {
+		return false
+	}
+	p.line = p.sc.Text()
+	p.num++
+	return true
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("tsp: line %d: "+format, append([]any{p.num}, args...)...)
+}
+
+// fields returns the next n whitespace-separated fields, which may span lines.
+func (p *parser) fields(n int) ([]string, error) {
+	var f []string
+	for len(f) < n {
+		if !p.next() {
+			if err := p.sc.Err(); err != nil {
+				return nil, err
+			}
+			return nil, p.errorf("unexpected end of data")
+		}
+		f = append(f, strings.Fields(p.line)...)
+	}
+	if len(f) > n {
+		return nil, p.errorf("unexpected data %q", strings.Join(f[n:], " "))
+	}
+	return f, nil
+}
+
+// coords reads a NODE_COORD_SECTION of in.n lines.
+func (p *parser) coords(in *Instance) error {
+	if in.n < 0 {
+		return p.errorf("NODE_COORD_SECTION before DIMENSION")
+	}
+	in.x, in.y = make([]float64, in.n), make([]float64, in.n)
+	seen := make([]bool, in.n)
+	for range in.n {
+		f, err := p.fields(3)
+		if err != nil {
+			return err
+		}
+		id, err := strconv.Atoi(f[0])
+		if err != nil || id < 1 || id > in.n || seen[id-1] {
+			return p.errorf("invalid node %q", f[0])
+		}
+		seen[id-1] = true
+		x, errx := strconv.ParseFloat(f[1], 64)
+		y, erry := strconv.ParseFloat(f[2], 64)
+		if errx != nil || erry != nil {
+			return p.errorf("invalid coordinates %q %q", f[1], f[2])
+		}
+		in.x[id-1], in.y[id-1] = x, y
+	}
+	return nil
+}
+
+// skip skips n lines.
+func (p *parser) skip(n int) error {
+	for range max(n, 0) {
+		if !p.next() {
+			return p.errorf("unexpected end of data")
+		}
+	}
+	return nil
+}
+
+// weights reads an EDGE_WEIGHT_SECTION in the given format.
+func (p *parser) weights(in *Instance, format string) error {
+	if in.n < 0 {
+		return p.errorf("EDGE_WEIGHT_SECTION before DIMENSION")
+	}
+	n := in.n
+	// Each format lists the entries (i, j) of one triangle or the whole matrix in a fixed order.
+	var cells [][2]int
+	switch format {
+	case "FULL_MATRIX":
+		for i := range n {
+			for j := range n {
+				cells = append(cells, [2]int{i, j})
+			}
+		}
+	case "UPPER_ROW", "UPPER_DIAG_ROW":
+		diag := format == "UPPER_DIAG_ROW"
+		for i := range n {
+			for j := i; j < n; j++ {
+				if j > i || diag {
+					cells = append(cells, [2]int{i, j})
+				}
+			}
+		}
+	case "LOWER_ROW", "LOWER_DIAG_ROW":
+		diag := format == "LOWER_DIAG_ROW"
+		for i := range n {
+			for j := 0; j <= i; j++ {
+				if j < i || diag {
+					cells = append(cells, [2]int{i, j})
+				}
+			}
+		}
+	default:
+		return p.errorf("unsupported EDGE_WEIGHT_FORMAT %q", format)
+	}
+	var vals []string
+	for len(vals) < len(cells) {
+		if !p.next() {
+			return p.errorf("unexpected end of data")
+		}
+		vals = append(vals, strings.Fields(p.line)...)
+	}
+	if len(vals) > len(cells) {
+		return p.errorf("unexpected data %q", strings.Join(vals[len(cells):], " "))
+	}
+	in.matrix = make([][]int64, n)
+	for i := range in.matrix {
+		in.matrix[i] = make([]int64, n)
+	}
+	for k, c := range cells {
+		v, err := strconv.ParseFloat(vals[k], 64)
+		if err != nil {
+			return p.errorf("invalid weight %q", vals[k])
+		}
+		in.matrix[c[0]][c[1]] = int64(v)
+		in.matrix[c[1]][c[0]] = int64(v)
+	}
+	return nil
+}
+
+// ParseTour reads a tour in the TSPLIB format, such as a published optimal tour, from r
+// and returns the cities it visits in order, numbered from 0.
+// If the file gives a DIMENSION, the tour must visit each of that many cities exactly once.
+func ParseTour(r io.Reader) ([]int, error) {
+	p := &parser{sc: bufio.NewScanner(r)}
+	n := -1
+	for p.next() {
+		key, value, _ := strings.Cut(p.line, ":")
+		switch strings.TrimSpace(key) {
+		case "DIMENSION":
+			d, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil || d < 1 {
+				return nil, p.errorf("invalid DIMENSION %q", strings.TrimSpace(value))
+			}
+			n = d
+		case "TOUR_SECTION":
+			return p.tour(n)
+		}
+	}
+	if err := p.sc.Err(); err != nil {
+		return nil, err
+	}
+	return nil, errors.New("tsp: missing TOUR_SECTION")
+}
+
+// tour reads a TOUR_SECTION of cities numbered from 1, terminated by -1 or the end of the data,
+// which must visit each of n cities exactly once unless n is negative.
+func (p *parser) tour(n int) ([]int, error) {
+	var order []int
+	seen := make([]bool, max(n, 0))
+	for p.next() {
+		for _, f := range strings.Fields(p.line) {
+			c, err := strconv.Atoi(f)
+			if err != nil {
+				return nil, p.errorf("invalid city %q", f)
+			}
+			if c == -1 {
+				return p.finish(order, n)
+			}
+			if c < 1 || n >= 0 && c > n {
+				return nil, p.errorf("city %d out of range", c)
+			}
+			if n >= 0 {
+				if seen[c-1] {
+					return nil, p.errorf("city %d visited twice", c)
+				}
+				seen[c-1] = true
+			}
+			order = append(order, c-1)
+		}
+	}
+	if err := p.sc.Err(); err != nil {
+		return nil, err
+	}
+	return p.finish(order, n)
+}
+
+// finish returns order, or an error if it does not visit all n cities, given that it visits none twice.
+func (p *parser) finish(order []int, n int) ([]int, error) {
+	if n >= 0 && len(order) != n {
+		return nil, p.errorf("tour visits %d of %d cities", len(order), n)
+	}
+	return order, nil
+}
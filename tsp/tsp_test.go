@@ -0,0 +1,156 @@
+package tsp
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	type dist struct {
+		i, j int
+		d    int64
+	}
+	for _, test := range []struct {
+		name  string
+		input string
+		n     int
+		dists []dist
+	}{
+		{
+			"EUC_2D",
+			"NAME: euc\nTYPE: TSP\nDIMENSION: 3\nEDGE_WEIGHT_TYPE: EUC_2D\nNODE_COORD_SECTION\n1 0 0\n2 3 4\n3 6.4 8\nEOF\n",
+			3,
+			[]dist{{0, 1, 5}, {1, 0, 5}, {0, 2, 10}, {1, 2, 5}, {2, 2, 0}},
+		},
+		{
+			"CEIL_2D",
+			"DIMENSION: 2\nEDGE_WEIGHT_TYPE: CEIL_2D\nNODE_COORD_SECTION\n1 0 0\n2 1 1\n",
+			2,
+			[]dist{{0, 1, 2}},
+		},
+		{
+			// The pseudo-Euclidean distance sqrt(100/10) = 3.16 rounds up.
+			"ATT",
+			"DIMENSION: 2\nEDGE_WEIGHT_TYPE: ATT\nNODE_COORD_SECTION\n2 10 0\n1 0 0\nEOF\n",
+			2,
+			[]dist{{0, 1, 4}},
+		},
+		{
+			// One degree of longitude on the equator is 111.3 km, and TSPLIB adds 1 before truncating.
+			"GEO",
+			"DIMENSION: 2\nEDGE_WEIGHT_TYPE: GEO\nNODE_COORD_SECTION\n1 0.00 0.00\n2 0.00 1.00\nEOF\n",
+			2,
+			[]dist{{0, 1, 112}},
+		},
+		{
+			"FULL_MATRIX",
+			"DIMENSION: 3\nEDGE_WEIGHT_TYPE: EXPLICIT\nEDGE_WEIGHT_FORMAT: FULL_MATRIX\nEDGE_WEIGHT_SECTION\n0 1 2\n1 0 3\n2 3 0\nEOF\n",
+			3,
+			[]dist{{0, 1, 1}, {0, 2, 2}, {2, 1, 3}},
+		},
+		{
+			"UPPER_ROW",
+			"DIMENSION: 3\nEDGE_WEIGHT_TYPE: EXPLICIT\nEDGE_WEIGHT_FORMAT: UPPER_ROW\nEDGE_WEIGHT_SECTION\n1 2\n3\nEOF\n",
+			3,
+			[]dist{{0, 1, 1}, {2, 0, 2}, {1, 2, 3}},
+		},
+		{
+			"LOWER_DIAG_ROW",
+			"DIMENSION: 3\nEDGE_WEIGHT_TYPE: EXPLICIT\nEDGE_WEIGHT_FORMAT: LOWER_DIAG_ROW\nDISPLAY_DATA_TYPE: TWOD_DISPLAY\nEDGE_WEIGHT_SECTION\n0 1 0 2 3 0\nDISPLAY_DATA_SECTION\n1 0 0\n2 1 0\n3 0 1\nEOF\n",
+			3,
+			[]dist{{1, 0, 1}, {0, 2, 2}, {2, 1, 3}, {1, 1, 0}},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			in, err := Parse(strings.NewReader(test.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if in.Len() != test.n {
+				t.Fatalf("Len = %d, want %d", in.Len(), test.n)
+			}
+			for _, d := range test.dists {
+				if got := in.Dist(d.i, d.j); got != d.d {
+					t.Errorf("Dist(%d, %d) = %d, want %d", d.i, d.j, got, d.d)
+				}
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	const coords = "EDGE_WEIGHT_TYPE: EUC_2D\nNODE_COORD_SECTION\n1 0 0\n2 3 4\n"
+	for _, test := range []struct {
+		name  string
+		input string
+		err   string
+	}{
+		{"unsupported TYPE", "TYPE: ATSP\nDIMENSION: 2\n" + coords, "unsupported TYPE ATSP"},
+		{"invalid DIMENSION", "DIMENSION: two\n" + coords, `invalid DIMENSION "two"`},
+		{"zero DIMENSION", "DIMENSION: 0\n" + coords, `invalid DIMENSION "0"`},
+		{"unsupported EDGE_WEIGHT_TYPE", "DIMENSION: 2\nEDGE_WEIGHT_TYPE: MAN_2D\n", "unsupported EDGE_WEIGHT_TYPE MAN_2D"},
+		{"unknown key", "DIMENSION: 2\nCAPACITY: 10\n" + coords, `unknown key "CAPACITY"`},
+		{"unknown section", "DIMENSION: 2\nDEMAND_SECTION\n" + coords, `unknown section "DEMAND_SECTION"`},
+		{"missing DIMENSION", coords[strings.Index(coords, "\n")+1:], "NODE_COORD_SECTION before DIMENSION"},
+		{"missing EDGE_WEIGHT_TYPE", "DIMENSION: 2\nNODE_COORD_SECTION\n1 0 0\n2 3 4\n", "missing EDGE_WEIGHT_TYPE"},
+		{"missing NODE_COORD_SECTION", "DIMENSION: 2\nEDGE_WEIGHT_TYPE: EUC_2D\nEOF\n", "missing NODE_COORD_SECTION"},
+		{"missing EDGE_WEIGHT_SECTION", "DIMENSION: 2\nEDGE_WEIGHT_TYPE: EXPLICIT\n", "missing EDGE_WEIGHT_SECTION"},
+		{"unsupported EDGE_WEIGHT_FORMAT", "DIMENSION: 2\nEDGE_WEIGHT_TYPE: EXPLICIT\nEDGE_WEIGHT_FORMAT: FUNCTION\nEDGE_WEIGHT_SECTION\n1\n", `unsupported EDGE_WEIGHT_FORMAT "FUNCTION"`},
+		{"truncated NODE_COORD_SECTION", "DIMENSION: 3\n" + coords, "unexpected end of data"},
+		{"truncated NODE_COORD_SECTION before EOF", "DIMENSION: 3\n" + coords + "EOF\n", "unexpected end of data"},
+		{"truncated EDGE_WEIGHT_SECTION", "DIMENSION: 3\nEDGE_WEIGHT_TYPE: EXPLICIT\nEDGE_WEIGHT_FORMAT: UPPER_ROW\nEDGE_WEIGHT_SECTION\n1 2\n", "unexpected end of data"},
+		{"excess weights", "DIMENSION: 2\nEDGE_WEIGHT_TYPE: EXPLICIT\nEDGE_WEIGHT_FORMAT: UPPER_ROW\nEDGE_WEIGHT_SECTION\n1 2\n", `unexpected data "2"`},
+		{"node out of range", "DIMENSION: 2\nEDGE_WEIGHT_TYPE: EUC_2D\nNODE_COORD_SECTION\n1 0 0\n3 3 4\n", `invalid node "3"`},
+		{"duplicate node", "DIMENSION: 2\nEDGE_WEIGHT_TYPE: EUC_2D\nNODE_COORD_SECTION\n1 0 0\n1 3 4\n", `invalid node "1"`},
+		{"invalid coordinates", "DIMENSION: 2\nEDGE_WEIGHT_TYPE: EUC_2D\nNODE_COORD_SECTION\n1 0 0\n2 x 4\n", `invalid coordinates "x" "4"`},
+		{"excess coordinates", "DIMENSION: 2\nEDGE_WEIGHT_TYPE: EUC_2D\nNODE_COORD_SECTION\n1 0 0 0\n2 3 4\n", `unexpected data "0"`},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			in, err := Parse(strings.NewReader(test.input))
+			if err == nil {
+				t.Fatalf("Parse returned an Instance of %d cities, want error containing %q", in.Len(), test.err)
+			}
+			if !strings.Contains(err.Error(), test.err) {
+				t.Errorf("error %q, want one containing %q", err, test.err)
+			}
+		})
+	}
+}
+
+func TestParseTour(t *testing.T) {
+	for _, test := range []struct {
+		name  string
+		input string
+		want  []int
+		err   string
+	}{
+		{"one per line", "NAME: t\nTYPE: TOUR\nDIMENSION: 3\nTOUR_SECTION\n1\n3\n2\n-1\nEOF\n", []int{0, 2, 1}, ""},
+		{"several per line", "DIMENSION: 4\nTOUR_SECTION\n2 1\n4 3 -1\n", []int{1, 0, 3, 2}, ""},
+		{"no terminator", "DIMENSION: 2\nTOUR_SECTION\n2\n1\n", []int{1, 0}, ""},
+		{"no DIMENSION", "TOUR_SECTION\n5 7\n-1\n", []int{4, 6}, ""},
+		{"city beyond DIMENSION", "DIMENSION: 3\nTOUR_SECTION\n1\n4\n2\n-1\n", nil, "city 4 out of range"},
+		{"city 0", "TOUR_SECTION\n1\n0\n-1\n", nil, "city 0 out of range"},
+		{"negative city", "DIMENSION: 3\nTOUR_SECTION\n1 -2 3 -1\n", nil, "city -2 out of range"},
+		{"city visited twice", "DIMENSION: 3\nTOUR_SECTION\n1\n2\n1\n-1\n", nil, "city 1 visited twice"},
+		{"incomplete tour", "DIMENSION: 3\nTOUR_SECTION\n1\n2\n-1\n", nil, "tour visits 2 of 3 cities"},
+		{"invalid city", "TOUR_SECTION\n1\nx\n-1\n", nil, `invalid city "x"`},
+		{"invalid DIMENSION", "DIMENSION: -3\nTOUR_SECTION\n1\n-1\n", nil, `invalid DIMENSION "-3"`},
+		{"missing TOUR_SECTION", "NAME: t\nDIMENSION: 3\n", nil, "missing TOUR_SECTION"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			order, err := ParseTour(strings.NewReader(test.input))
+			switch {
+			case test.err == "" && err != nil:
+				t.Fatal(err)
+			case test.err != "" && err == nil:
+				t.Fatalf("ParseTour = %v, want error containing %q", order, test.err)
+			case test.err != "" && !strings.Contains(err.Error(), test.err):
+				t.Fatalf("error %q, want one containing %q", err, test.err)
+			}
+			if !slices.Equal(order, test.want) {
+				t.Errorf("ParseTour = %v, want %v", order, test.want)
+			}
+		})
+	}
+}
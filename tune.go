@@ -0,0 +1,118 @@
+package anneal
+
+import (
+	"fmt"
+	"math"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// A Report compares the outcomes of several Schedules.
+type Report struct {
+	Schedules []ScheduleReport // one per candidate, in the order given
+	Best      int              // index of the candidate with the lowest mean energy, or -1 if none succeeded
+}
+
+// A ScheduleReport summarizes the runs of one candidate Schedule.
+type ScheduleReport struct {
+	Schedule *Schedule
+	Runs     int   // number of runs that succeeded
+	Errors   int   // number of runs that failed
+	Err      error // first error, if any
+
+	Mean, StdDev float64       // mean and sample standard deviation of the final energies
+	Best, Worst  float64       // lowest and highest final energies
+	BestState    State         // State of energy Best
+	Time         time.Duration // mean wall time per run
+}
+
+func (r Report) String() string {
+	var b strings.Builder
+	for i, s := range r.Schedules {
+		mark := " "
+		if i == r.Best {
+			mark = "*"
+		}
+		fmt.Fprintf(&b, "%s%d: %d runs, mean %v ± %v, best %v, worst %v, %v per run", mark, i, s.Runs, s.Mean, s.StdDev, s.Best, s.Worst, s.Time)
+		if s.Errors > 0 {
+			fmt.Fprintf(&b, ", %d errors: %v", s.Errors, s.Err)
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// Tune runs each candidate Schedule runsEach times from s, concurrently on up to GOMAXPROCS goroutines,
+// and reports statistics of the final energies, so that Schedules can be compared systematically.
+// Because runs proceed concurrently, s must be safe to use from several goroutines,
+// as must any Observer, Policy, or Acceptor of the Schedules, and their Rand and Seed fields are not used.
+func Tune(s State, schedules []*Schedule, runsEach int) Report {
+	type outcome struct {
+		r   Result
+		err error
+		t   time.Duration
+	}
+	out := make([][]outcome, len(schedules))
+	for i := range out {
+		out[i] = make([]outcome, runsEach)
+	}
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, runtime.GOMAXPROCS(0))
+	for i, sch := range schedules {
+		c := *sch
+		c.Rand, c.UseSeed = nil, false
+		for j := 0; j < runsEach; j++ {
+			wg.Add(1)
+			sem <- struct{}{}
+			go func() {
+				defer func() { <-sem; wg.Done() }()
+				start := time.Now()
+				r, err := Run(s, &c)
+				out[i][j] = outcome{r, err, time.Since(start)}
+			}()
+		}
+	}
+	wg.Wait()
+
+	rep := Report{Schedules: make([]ScheduleReport, len(schedules)), Best: -1}
+	for i, runs := range out {
+		sr := ScheduleReport{Schedule: schedules[i], Best: math.Inf(1), Worst: math.Inf(-1)}
+		var sum, sumsq float64
+		var total time.Duration
+		for _, o := range runs {
+			if o.err != nil {
+				sr.Errors++
+				if sr.Err == nil {
+					sr.Err = o.err
+				}
+				continue
+			}
+			sr.Runs++
+			e := o.r.Energy
+			sum += e
+			sumsq += e * e
+			total += o.t
+			if e < sr.Best {
+				sr.Best, sr.BestState = e, o.r.Best
+			}
+			sr.Worst = max(sr.Worst, e)
+		}
+		if sr.Runs == 0 {
+			sr.Mean, sr.StdDev, sr.Best, sr.Worst = math.NaN(), math.NaN(), math.NaN(), math.NaN()
+		} else {
+			n := float64(sr.Runs)
+			sr.Mean = sum / n
+			if sr.Runs > 1 {
+				sr.StdDev = math.Sqrt(max(0, (sumsq-sum*sum/n)/(n-1)))
+			}
+			sr.Time = total / time.Duration(sr.Runs)
+			if rep.Best < 0 || sr.Mean < rep.Schedules[rep.Best].Mean {
+				rep.Best = i
+			}
+		}
+		rep.Schedules[i] = sr
+	}
+	return rep
+}
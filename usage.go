@@ -0,0 +1,94 @@
+package anneal
+
+import (
+	"fmt"
+	"runtime/metrics"
+	"time"
+)
+
+// A Usage describes the resources consumed by an annealing run.
+// The Go runtime accounts for CPU time, allocation, and garbage collection per process, not per goroutine,
+// so figures include any work the program performs concurrently with the run.
+type Usage struct {
+	Wall      time.Duration // elapsed time
+	CPU       time.Duration // CPU time consumed by the process, including garbage collection
+	GCCPU     time.Duration // CPU time spent in garbage collection
+	GCCycles  uint64        // number of completed garbage collection cycles
+	Allocated uint64        // bytes allocated on the heap
+
+	// PeakMemory is the largest amount of memory mapped by the Go runtime observed at the start or end of any run.
+	// It estimates peak resident set size from samples and may miss transient peaks between them.
+	PeakMemory uint64
+}
+
+func (u Usage) String() string {
+	return fmt.Sprintf("wall %v, cpu %v (gc %v), %d gc cycles, %d bytes allocated, peak memory %d bytes",
+		u.Wall, u.CPU, u.GCCPU, u.GCCycles, u.Allocated, u.PeakMemory)
+}
+
+// usageMetrics lists the runtime metrics sampled to compute Usage, in the order read by sampleUsage.
+var usageMetrics = []string{
+	"/cpu/classes/total:cpu-seconds",
+	"/cpu/classes/gc/total:cpu-seconds",
+	"/gc/cycles/total:gc-cycles",
+	"/gc/heap/allocs:bytes",
+	"/memory/classes/total:bytes",
+}
+
+// A usageSample is a reading of the cumulative resource counters at an instant.
+type usageSample struct {
+	t         time.Time
+	cpu, gc   float64
+	cycles    uint64
+	allocated uint64
+	memory    uint64
+}
+
+func sampleUsage() usageSample {
+	m := make([]metrics.Sample, len(usageMetrics))
+	for i, name := range usageMetrics {
+		m[i].Name = name
+	}
+	metrics.Read(m)
+	u := usageSample{t: time.Now()}
+	if cpu, ok := processCPU(); ok {
+		u.cpu = cpu.Seconds()
+	} else if m[0].Value.Kind() == metrics.KindFloat64 {
+		u.cpu = m[0].Value.Float64()
+	}
+	if m[1].Value.Kind() == metrics.KindFloat64 {
+		u.gc = m[1].Value.Float64()
+	}
+	if m[2].Value.Kind() == metrics.KindUint64 {
+		u.cycles = m[2].Value.Uint64()
+	}
+	if m[3].Value.Kind() == metrics.KindUint64 {
+		u.allocated = m[3].Value.Uint64()
+	}
+	if m[4].Value.Kind() == metrics.KindUint64 {
+		u.memory = m[4].Value.Uint64()
+	}
+	return u
+}
+
+// since returns the resources consumed between the samples from and u,
+// with peak as the peak memory observed.
+func (u usageSample) since(from usageSample, peak uint64) Usage {
+	return Usage{
+		Wall:       u.t.Sub(from.t),
+		CPU:        seconds(u.cpu - from.cpu),
+		GCCPU:      seconds(u.gc - from.gc),
+		GCCycles:   u.cycles - from.cycles,
+		Allocated:  u.allocated - from.allocated,
+		PeakMemory: max(peak, from.memory, u.memory),
+	}
+}
+
+func seconds(s float64) time.Duration { return time.Duration(s * float64(time.Second)) }
+
+// measure records the resources consumed by the run that began with the sample from.
+func (a *annealer) measure(from usageSample) {
+	u := sampleUsage().since(from, 0)
+	a.peak = max(a.peak, u.PeakMemory)
+	a.usage = append(a.usage, u)
+}
@@ -0,0 +1,8 @@
+//go:build !unix
+
+package anneal
+
+import "time"
+
+// processCPU reports that the operating system's accounting of CPU time is unavailable.
+func processCPU() (time.Duration, bool) { return 0, false }
@@ -0,0 +1,18 @@
+//go:build unix
+
+package anneal
+
+import (
+	"syscall"
+	"time"
+)
+
+// processCPU returns the CPU time consumed by the process in user and system mode.
+// The runtime's own estimate is refreshed only at garbage collections, so the operating system's is preferred.
+func processCPU() (time.Duration, bool) {
+	var ru syscall.Rusage
+	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &ru); err != nil {
+		return 0, false
+	}
+	return time.Duration(ru.Utime.Nano() + ru.Stime.Nano()), true
+}
@@ -0,0 +1,99 @@
+package anneal
+
+import (
+	"fmt"
+	"math"
+)
+
+// Validate reports whether sch describes a meaningful annealing process.
+// It returns an error describing the first problem found, if any.
+func (sch *Schedule) Validate() error {
+	switch {
+	case sch.Duration < 0:
+		return scheduleError("Duration %v is negative", sch.Duration)
+	case sch.Duration == 0 && sch.Iter <= 0:
+		return scheduleError("Iter %d is not positive", sch.Iter)
+	case !finite(sch.Ti) || !finite(sch.Tf):
+		return scheduleError("temperatures Ti = %v and Tf = %v must be finite", sch.Ti, sch.Tf)
+	case sch.Tf <= 0:
+		return scheduleError("Tf %v is not positive", sch.Tf)
+	case sch.Ti <= sch.Tf:
+		return scheduleError("Ti %v does not exceed Tf %v", sch.Ti, sch.Tf)
+	case sch.Cooling < CoolExponential || sch.Cooling > CoolLundyMees:
+		return scheduleError("unknown Cooling %d", sch.Cooling)
+	case math.IsNaN(sch.CoolRate) || sch.CoolRate < 0:
+		return scheduleError("CoolRate %v is not a nonnegative number", sch.CoolRate)
+	case sch.CoolRate > 0 && sch.Cooling == CoolExponential:
+		return scheduleError("CoolRate %v requires a Cooling other than CoolExponential", sch.CoolRate)
+	case sch.Cooling == CoolGeometric && sch.CoolRate >= 1:
+		return scheduleError("CoolRate %v of CoolGeometric is not less than 1", sch.CoolRate)
+	case sch.Cooling == CoolLundyMees && math.IsInf(sch.CoolRate, 1):
+		return scheduleError("CoolRate of CoolLundyMees is infinite")
+	case sch.Block < 0:
+		return scheduleError("Block %d is negative", sch.Block)
+	case sch.Block > 1 && sch.Duration > 0:
+		return scheduleError("Block %d requires runs measured in iterations, not Duration", sch.Block)
+	case sch.Restarts < 0:
+		return scheduleError("Restarts %d is negative", sch.Restarts)
+	case !finite(sch.Diversify):
+		return scheduleError("Diversify %v is not finite", sch.Diversify)
+	case sch.Workers < 0:
+		return scheduleError("Workers %d is negative", sch.Workers)
+	case math.IsNaN(sch.Scan) || sch.Scan < 0:
+		return scheduleError("Scan %v is not a nonnegative number", sch.Scan)
+	case sch.Audit < 0:
+		return scheduleError("Audit %d is negative", sch.Audit)
+	case math.IsNaN(sch.MaxOverhead) || sch.MaxOverhead < 0 || sch.MaxOverhead >= 1:
+		return scheduleError("MaxOverhead %v is not in [0, 1)", sch.MaxOverhead)
+	case !(sch.AcceptTarget >= 0 && sch.AcceptTarget < 1):
+		return scheduleError("AcceptTarget %v is not in [0, 1)", sch.AcceptTarget)
+	case sch.ScaleWindow < 0:
+		return scheduleError("ScaleWindow %d is negative", sch.ScaleWindow)
+	case sch.Samples < 0 || sch.MaxSamples < 0:
+		return scheduleError("Samples %d and MaxSamples %d must not be negative", sch.Samples, sch.MaxSamples)
+	case sch.Evals < 0:
+		return scheduleError("Evals %d is negative", sch.Evals)
+	case sch.UseTarget && math.IsNaN(sch.Target):
+		return scheduleError("Target is NaN")
+	case sch.Polish < 0:
+		return scheduleError("Polish %d is negative", sch.Polish)
+	case !(sch.Rate >= 0) || math.IsInf(sch.Rate, 1):
+		return scheduleError("Rate %v is not a finite nonnegative number", sch.Rate)
+	case sch.Yield < 0:
+		return scheduleError("Yield %d is negative", sch.Yield)
+	case sch.LocalEvery < 0 || sch.LocalBudget < 0:
+		return scheduleError("LocalEvery %d and LocalBudget %d must not be negative", sch.LocalEvery, sch.LocalBudget)
+	case sch.History < 0:
+		return scheduleError("History %d is negative", sch.History)
+	case sch.NonFinite < RejectNonFinite || sch.NonFinite > InfeasibleInf:
+		return scheduleError("unknown NonFinite policy %d", sch.NonFinite)
+	case math.IsNaN(sch.Plateau) || sch.Plateau < 0:
+		return scheduleError("Plateau %v is not a nonnegative number", sch.Plateau)
+	case !(sch.PlateauAccept >= 0 && sch.PlateauAccept <= 1):
+		return scheduleError("PlateauAccept %v is not in [0, 1]", sch.PlateauAccept)
+	case sch.UseSeed && sch.Workers > 1:
+		return scheduleError("Seed requires Workers of at most 1, not %d", sch.Workers)
+	case sch.UseSeed && sch.Rand != nil:
+		return scheduleError("Seed and Rand are exclusive")
+	case sch.UseSeed && sch.Shared != nil:
+		return scheduleError("Seed and Shared are exclusive")
+	case sch.Exchange < 0:
+		return scheduleError("Exchange %d is negative", sch.Exchange)
+	case sch.Exchange > 0 && sch.Shared == nil:
+		return scheduleError("Exchange %d requires Shared", sch.Exchange)
+	case sch.Keep < 0:
+		return scheduleError("Keep %d is negative", sch.Keep)
+	case sch.Recent < 0:
+		return scheduleError("Recent %d is negative", sch.Recent)
+	}
+	return nil
+}
+
+func scheduleError(format string, args ...any) error {
+	return fmt.Errorf("anneal: invalid Schedule: "+format, args...)
+}
+
+// finite reports whether x is neither infinite nor NaN.
+func finite(x float64) bool {
+	return !math.IsInf(x, 0) && !math.IsNaN(x)
+}
@@ -0,0 +1,78 @@
+package anneal
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidate(t *testing.T) {
+	if err := NewSchedule().Validate(); err != nil {
+		t.Errorf("default Schedule: %v", err)
+	}
+	for _, test := range []struct {
+		name string
+		edit func(sch *Schedule)
+		err  string // part of the error, or "" if the Schedule is valid
+	}{
+		{"zero Iter", func(sch *Schedule) { sch.Iter = 0 }, "Iter 0 is not positive"},
+		{"negative Iter", func(sch *Schedule) { sch.Iter = -1 }, "Iter -1 is not positive"},
+		{"zero Iter with Duration", func(sch *Schedule) { sch.Iter, sch.Duration = 0, time.Second }, ""},
+		{"negative Duration", func(sch *Schedule) { sch.Duration = -time.Second }, "Duration -1s is negative"},
+		{"Ti equal to Tf", func(sch *Schedule) { sch.Ti, sch.Tf = 1, 1 }, "Ti 1 does not exceed Tf 1"},
+		{"Ti below Tf", func(sch *Schedule) { sch.Ti, sch.Tf = 0.1, 1 }, "Ti 0.1 does not exceed Tf 1"},
+		{"zero Tf", func(sch *Schedule) { sch.Tf = 0 }, "Tf 0 is not positive"},
+		{"negative Tf", func(sch *Schedule) { sch.Ti, sch.Tf = 1, -1 }, "Tf -1 is not positive"},
+		{"NaN Ti", func(sch *Schedule) { sch.Ti = math.NaN() }, "must be finite"},
+		{"NaN Tf", func(sch *Schedule) { sch.Tf = math.NaN() }, "must be finite"},
+		{"infinite Ti", func(sch *Schedule) { sch.Ti = math.Inf(1) }, "must be finite"},
+		{"NaN CoolRate", func(sch *Schedule) { sch.Cooling, sch.CoolRate = CoolGeometric, math.NaN() }, "CoolRate NaN"},
+		{"unknown Cooling", func(sch *Schedule) { sch.Cooling = 99 }, "unknown Cooling 99"},
+		{"NaN Target", func(sch *Schedule) { sch.Target, sch.UseTarget = math.NaN(), true }, "Target is NaN"},
+		{"NaN Target unused", func(sch *Schedule) { sch.Target = math.NaN() }, ""},
+		{"Seed with Workers", func(sch *Schedule) { sch.UseSeed, sch.Workers = true, 2 }, "Seed requires Workers of at most 1"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			sch := NewSchedule()
+			test.edit(sch)
+			err := sch.Validate()
+			switch {
+			case test.err == "" && err != nil:
+				t.Errorf("Validate = %v, want nil", err)
+			case test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)):
+				t.Errorf("Validate = %v, want an error containing %q", err, test.err)
+			}
+		})
+	}
+}
+
+func TestAnnealRejectsInvalidSchedule(t *testing.T) {
+	for _, opts := range [][]Option{
+		{WithIterations(0)},
+		{WithTemperatures(1e-5, 1)},
+		{WithTemperatures(1, 0)},
+		{WithTemperatures(math.NaN(), 1e-5)},
+	} {
+		var calls int
+		s := countedLine{line(5), &calls}
+		best, err := Anneal(s, opts...)
+		if err == nil || !strings.HasPrefix(err.Error(), "anneal: invalid Schedule: ") {
+			t.Errorf("Anneal returned error %v, want an invalid Schedule", err)
+		}
+		if best != nil || calls != 0 {
+			t.Errorf("Anneal returned %v after %d calls to Neighbor, want nil without annealing", best, calls)
+		}
+	}
+}
+
+// A countedLine is a line that counts the neighbors it proposes.
+type countedLine struct {
+	line
+	calls *int
+}
+
+func (x countedLine) Neighbor() State {
+	*x.calls++
+	return countedLine{x.line.Neighbor().(line), x.calls}
+}
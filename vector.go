@@ -0,0 +1,142 @@
+package anneal
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+)
+
+// A Proposal is a distribution from which VectorState draws its steps.
+type Proposal int
+
+const (
+	// Gaussian steps have standard deviation Step * sqrt(T).
+	Gaussian Proposal = iota
+
+	// Cauchy steps have scale parameter Step * T. Their heavy tails allow occasional long jumps
+	// even late in the schedule.
+	Cauchy
+)
+
+// A VectorProblem describes the minimization of a function of a vector of real numbers.
+type VectorProblem struct {
+	Func     func(x []float64) float64 // objective function; must not retain or modify x
+	Lower    []float64                 // lower bound of each coordinate, or nil if unbounded below
+	Upper    []float64                 // upper bound of each coordinate, or nil if unbounded above
+	Step     float64                   // proposal scale
+	Proposal Proposal                  // step distribution
+}
+
+// NewState returns a VectorState of p located at x. It does not copy x.
+func (p *VectorProblem) NewState(x []float64) *VectorState {
+	return &VectorState{X: x, p: p}
+}
+
+// A VectorState is a point in the search space of a VectorProblem.
+// Its energy is the value of the objective function.
+// Each neighbor differs from it in a single randomly chosen coordinate,
+// displaced by a step whose size shrinks with the annealing temperature T
+// as specified by the Proposal, but not below 1e-6 Step, and reflected as necessary to stay within bounds.
+// A VectorState of an empty vector is its own only neighbor.
+// VectorState implements Tempered, Scaler by multiplying its steps by the factor, which its neighbors inherit,
+// Seeded, and Recycler by drawing the storage of its neighbors from a pool.
+type VectorState struct {
+	X     []float64
+	p     *VectorProblem
+	scale float64 // factor set by Scale, or 0 for 1
+	rand  source
+}
+
+// Energy returns the value of the objective function at v.X.
+func (v *VectorState) Energy() float64 { return v.p.Func(v.X) }
+
+// Neighbor returns a neighbor of v with the step scale evaluated at T = 1.
+func (v *VectorState) Neighbor() State { return v.NeighborT(1) }
+
+// NeighborT returns a neighbor of v with the step scale evaluated at temperature T.
+func (v *VectorState) NeighborT(T float64) State {
+	n := v.CloneInto(vectorStates.Get().(*VectorState))
+	x := n.X
+	if len(x) == 0 {
+		return n
+	}
+	i := v.rand.Intn(len(x))
+	var step float64
+	switch v.p.Proposal {
+	case Cauchy:
+		step = v.p.Step * max(T, minStep) * math.Tan(math.Pi*(v.rand.Float64()-0.5))
+	default:
+		step = v.p.Step * max(math.Sqrt(T), minStep) * v.rand.NormFloat64()
+	}
+	if v.scale != 0 {
+		step *= v.scale
+	}
+	x[i] = v.p.bound(i, x[i]+step)
+	return n
+}
+
+// Release returns the storage of v to the pool from which NeighborT draws.
+func (v *VectorState) Release() { vectorStates.Put(v) }
+
+// vectorStates holds released VectorStates for reuse.
+var vectorStates = sync.Pool{New: func() any { return new(VectorState) }}
+
+// minStep is the smallest fraction of Step to which the temperature shrinks the scale of the moves
+// of a VectorState or SimplexState, so that a search whose temperature is zero, such as one from a State of zero energy, still moves.
+const minStep = 1e-6
+
+// Scale sets the factor by which to multiply the steps of v and its neighbors.
+func (v *VectorState) Scale(f float64) { v.scale = f }
+
+// UseRand sets the source of randomness of v and its neighbors.
+func (v *VectorState) UseRand(r *rand.Rand) { v.rand.r = r }
+
+// CloneInto copies v into dst, reusing the storage of dst.X if it is large enough, and returns dst.
+// If dst is nil, it allocates a new VectorState. NeighborT uses it to fill released States;
+// programs that manage their own pools of States can use it likewise.
+func (v *VectorState) CloneInto(dst *VectorState) *VectorState {
+	if dst == nil {
+		dst = new(VectorState)
+	}
+	dst.X = append(dst.X[:0], v.X...)
+	dst.p, dst.scale, dst.rand = v.p, v.scale, v.rand
+	return dst
+}
+
+// bound reflects x into the bounds of coordinate i.
+func (p *VectorProblem) bound(i int, x float64) float64 {
+	lo, hi := math.Inf(-1), math.Inf(1)
+	if p.Lower != nil {
+		lo = p.Lower[i]
+	}
+	if p.Upper != nil {
+		hi = p.Upper[i]
+	}
+	return reflect(x, lo, hi)
+}
+
+// reflect reflects x into the interval [lo, hi].
+func reflect(x, lo, hi float64) float64 {
+	switch {
+	case x >= lo && x <= hi:
+		return x
+	case math.IsInf(hi, 1):
+		return 2*lo - x
+	case math.IsInf(lo, -1):
+		return 2*hi - x
+	}
+	w := hi - lo
+	if w == 0 {
+		// The interval is a single point.
+		return lo
+	}
+	// Fold x into a period of 2*(hi-lo), then reflect the upper half.
+	y := math.Mod(x-lo, 2*w)
+	if y < 0 {
+		y += 2 * w
+	}
+	if y > w {
+		y = 2*w - y
+	}
+	return math.Min(math.Max(lo+y, lo), hi)
+}
@@ -0,0 +1,77 @@
+package anneal
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func benchVector() *VectorState {
+	p := &VectorProblem{Func: func(x []float64) float64 { return x[0] }, Step: 1}
+	return p.NewState(make([]float64, 1000))
+}
+
+func BenchmarkVectorNeighbor(b *testing.B) {
+	s := benchVector()
+	b.ReportAllocs()
+	for b.Loop() {
+		s.Neighbor().(Recycler).Release()
+	}
+}
+
+func BenchmarkVectorCloneInto(b *testing.B) {
+	s := benchVector()
+	dst := s.CloneInto(nil)
+	b.ReportAllocs()
+	for b.Loop() {
+		s.CloneInto(dst)
+	}
+}
+
+func TestVectorNeighbor(t *testing.T) {
+	p := &VectorProblem{Func: func(x []float64) float64 { return 0 }, Step: 1}
+	if n := p.NewState(nil).NeighborT(1).(*VectorState); len(n.X) != 0 {
+		t.Errorf("neighbor of empty vector has X = %v", n.X)
+	}
+	for _, proposal := range []Proposal{Gaussian, Cauchy} {
+		p.Proposal = proposal
+		s := p.NewState([]float64{0})
+		if n := s.NeighborT(0).(*VectorState); n.X[0] == 0 {
+			t.Errorf("Proposal %d: neighbor at T = 0 did not move", proposal)
+		}
+	}
+
+	// A coordinate whose bounds coincide stays at its only value.
+	p = &VectorProblem{Func: func(x []float64) float64 { return 0 }, Lower: []float64{2, 0}, Upper: []float64{2, 1}, Step: 1}
+	s := p.NewState([]float64{2, 0.5})
+	for range 100 {
+		n := s.Neighbor().(*VectorState)
+		if n.X[0] != 2 || !(n.X[1] >= 0 && n.X[1] <= 1) {
+			t.Fatalf("neighbor within bounds [2, 2] x [0, 1] is %v", n.X)
+		}
+		s = n
+	}
+}
+
+func TestVectorSeeded(t *testing.T) {
+	p := &VectorProblem{
+		Func: func(x []float64) float64 {
+			var sum float64
+			for _, xi := range x {
+				sum += xi*xi - 10*math.Cos(2*math.Pi*xi)
+			}
+			return sum
+		},
+		Step: 1,
+	}
+	run := func() []float64 {
+		best, err := Anneal(p.NewState([]float64{3, -2, 4}), WithIterations(10000), WithTemperatures(10, 0.01), WithSeed(1))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return best.(*VectorState).X
+	}
+	if x, y := run(), run(); !slices.Equal(x, y) {
+		t.Errorf("seeded runs returned %v and %v", x, y)
+	}
+}